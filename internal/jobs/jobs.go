@@ -0,0 +1,129 @@
+// Package jobs persists per-title conversion state to a local BoltDB file
+// so long crawl runs over thousands of titles can resume after a crash or
+// Ctrl-C instead of losing progress held only in memory.
+package jobs
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Status is where a title sits in the fetch -> render pipeline.
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusFetched  Status = "fetched"
+	StatusRendered Status = "rendered"
+	StatusFailed   Status = "failed"
+)
+
+var bucketName = []byte("jobs")
+
+// Record is the persisted state for one (lang, title, target) triple.
+// Target identifies what was being produced (e.g. the renderer set and
+// whether it was a -bundle run), so re-running the same title against a
+// different output configuration doesn't see it as already done.
+type Record struct {
+	Lang      string    `json:"lang"`
+	Title     string    `json:"title"`
+	Target    string    `json:"target"`
+	Status    Status    `json:"status"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"last_error,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Store wraps a BoltDB file keyed by "lang\x00title\x00target".
+type Store struct {
+	db *bolt.DB
+}
+
+// Open opens (creating if needed) the BoltDB file at path.
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0o644, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open job store: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketName)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("init job store: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close releases the underlying BoltDB file.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func key(lang, title, target string) []byte {
+	return []byte(lang + "\x00" + title + "\x00" + target)
+}
+
+// Get returns the stored record for (lang, title, target), or ok=false
+// if there is none yet.
+func (s *Store) Get(lang, title, target string) (rec Record, ok bool, err error) {
+	err = s.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(bucketName).Get(key(lang, title, target))
+		if v == nil {
+			return nil
+		}
+		ok = true
+		return json.Unmarshal(v, &rec)
+	})
+	return rec, ok, err
+}
+
+// Save upserts rec, stamping UpdatedAt.
+func (s *Store) Save(rec Record) error {
+	rec.UpdatedAt = time.Now()
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketName).Put(key(rec.Lang, rec.Title, rec.Target), data)
+	})
+}
+
+// All returns every record currently in the store.
+func (s *Store) All() ([]Record, error) {
+	var out []Record
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketName).ForEach(func(_, v []byte) error {
+			var rec Record
+			if err := json.Unmarshal(v, &rec); err != nil {
+				return err
+			}
+			out = append(out, rec)
+			return nil
+		})
+	})
+	return out, err
+}
+
+// Backoff returns how long to wait before retrying a title that has
+// already failed attempts times, using exponential backoff with jitter
+// capped at max.
+func Backoff(attempts int, base, max time.Duration) time.Duration {
+	if attempts < 1 {
+		return 0
+	}
+	d := base << uint(attempts-1) // base * 2^(attempts-1)
+	if d > max || d <= 0 {
+		d = max
+	}
+	jitter := time.Duration(rand.Int63n(int64(d)/2 + 1))
+	return d/2 + jitter
+}
@@ -0,0 +1,43 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffZeroAttempts(t *testing.T) {
+	if d := Backoff(0, time.Second, time.Minute); d != 0 {
+		t.Fatalf("got %v, want 0", d)
+	}
+}
+
+func TestBackoffGrowsWithAttempts(t *testing.T) {
+	base := time.Second
+	max := time.Hour
+
+	// Backoff has jitter, so compare against the upper bound
+	// (base*2^(attempts-1)) rather than an exact value.
+	ceiling := func(attempts int) time.Duration {
+		return base << uint(attempts-1)
+	}
+
+	for attempts := 1; attempts <= 5; attempts++ {
+		d := Backoff(attempts, base, max)
+		if d < 0 {
+			t.Fatalf("attempt %d: got negative duration %v", attempts, d)
+		}
+		if d > ceiling(attempts) {
+			t.Fatalf("attempt %d: got %v, want <= %v", attempts, d, ceiling(attempts))
+		}
+	}
+}
+
+func TestBackoffCapsAtMax(t *testing.T) {
+	max := 10 * time.Second
+	for attempts := 10; attempts <= 12; attempts++ {
+		d := Backoff(attempts, time.Second, max)
+		if d > max {
+			t.Fatalf("attempt %d: got %v, want <= max %v", attempts, d, max)
+		}
+	}
+}
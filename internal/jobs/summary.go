@@ -0,0 +1,51 @@
+package jobs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Summary is the end-of-run report written alongside the job store so a
+// crawl's outcome survives after the process exits.
+type Summary struct {
+	StartedAt  time.Time      `json:"started_at"`
+	FinishedAt time.Time      `json:"finished_at"`
+	Duration   string         `json:"duration"`
+	Counts     map[Status]int `json:"counts"`
+	Titles     []Record       `json:"titles"`
+}
+
+// BuildSummary tallies records into a Summary spanning [started, finished).
+func BuildSummary(records []Record, started, finished time.Time) Summary {
+	counts := map[Status]int{
+		StatusPending:  0,
+		StatusFetched:  0,
+		StatusRendered: 0,
+		StatusFailed:   0,
+	}
+	for _, r := range records {
+		counts[r.Status]++
+	}
+
+	return Summary{
+		StartedAt:  started,
+		FinishedAt: finished,
+		Duration:   finished.Sub(started).String(),
+		Counts:     counts,
+		Titles:     records,
+	}
+}
+
+// WriteSummary writes s as indented JSON to path.
+func WriteSummary(s Summary, path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal summary: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("write summary: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,498 @@
+// Package docx renders an article (or a whole collection, as a bundle)
+// into a .docx file: headings, lists, tables, hyperlinks and images are
+// emitted as raw WordprocessingML, since the format has no external
+// dependencies to lean on here.
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/w0ikid/wiki2docx/internal/render"
+	"github.com/w0ikid/wiki2docx/internal/wiki"
+	"github.com/w0ikid/wiki2docx/internal/wikitext"
+)
+
+func init() {
+	render.Register(Renderer{})
+}
+
+// Renderer implements render.Renderer and render.BundleRenderer for DOCX.
+type Renderer struct{}
+
+func (Renderer) Name() string { return "docx" }
+
+func (Renderer) Render(ctx context.Context, a render.Article, outDir string) error {
+	return Build(ctx, a.Title, a.Wikitext, outDir, a.Lang)
+}
+
+func (Renderer) RenderBundle(ctx context.Context, articles []render.Article, outDir, bundleName string) error {
+	return BuildBundle(ctx, articles, outDir, bundleName)
+}
+
+var unsafeChars = regexp.MustCompile(`[\\/:*?"<>| ]+`)
+
+// safeFilename converts an article title into a safe filename.
+func safeFilename(title string) string {
+	s := unsafeChars.ReplaceAllString(title, "_")
+	s = strings.Trim(s, "_")
+	if len(s) > 200 {
+		s = s[:200]
+	}
+	return s
+}
+
+// Build parses the article's MediaWiki wikitext and writes a structured
+// .docx file (headings, lists, tables, links, images) into outDir.
+// lang is the Wikipedia language prefix (e.g. "en"), used to resolve
+// [[wikilinks]] and to download [[File:...]] images.
+func Build(ctx context.Context, title, wikitextSrc, outDir, lang string) error {
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return fmt.Errorf("create output dir: %w", err)
+	}
+	if lang == "" {
+		lang = wikitext.DefaultLangPrefix
+	}
+
+	b := newDocBuilder(ctx, lang)
+	b.renderTitle(title)
+	for _, n := range wikitext.ParseBlocksForLang(wikitextSrc, lang) {
+		b.renderNode(n)
+	}
+
+	filename := safeFilename(title) + ".docx"
+	outPath := filepath.Join(outDir, filename)
+	if err := writeDocx(outPath, b); err != nil {
+		return fmt.Errorf("save docx: %w", err)
+	}
+	return nil
+}
+
+// BuildBundle packs many articles into a single .docx with a linked
+// table of contents up front and a page break between each article,
+// mirroring how offline Wikipedia readers package multi-article bundles.
+func BuildBundle(ctx context.Context, articles []render.Article, outDir, bundleName string) error {
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return fmt.Errorf("create output dir: %w", err)
+	}
+
+	b := newDocBuilder(ctx, "")
+	b.renderTitle(bundleName)
+	b.renderTOC(articles)
+
+	for i, a := range articles {
+		lang := a.Lang
+		if lang == "" {
+			lang = wikitext.DefaultLangPrefix
+		}
+		b.lang = lang
+		b.breakList()
+		b.body.WriteString(`<w:p><w:r><w:br w:type="page"/></w:r></w:p>`)
+		b.renderBookmarkedTitle(i, a.Title)
+		for _, n := range wikitext.ParseBlocksForLang(a.Wikitext, lang) {
+			b.renderNode(n)
+		}
+	}
+
+	filename := safeFilename(bundleName) + ".docx"
+	outPath := filepath.Join(outDir, filename)
+	if err := writeDocx(outPath, b); err != nil {
+		return fmt.Errorf("save bundle docx: %w", err)
+	}
+	return nil
+}
+
+// docBuilder accumulates document.xml body markup along with the
+// relationships and media parts it references (hyperlinks, images).
+type docBuilder struct {
+	ctx     context.Context
+	lang    string
+	body    strings.Builder
+	rels    []relationship
+	media   []mediaPart
+	nextRID int
+
+	nums         []numInstance // one per contiguous run of list items
+	curListNumID int           // numId of the run currently being written, 0 if none
+	curOrdered   bool          // Ordered flag of the run curListNumID belongs to
+}
+
+// numInstance is one <w:num> written into numbering.xml: a fresh numId
+// bound to either the bullet or decimal abstractNum, so each contiguous
+// run of list items gets its own independent numbering sequence instead
+// of sharing one counter across the whole document.
+type numInstance struct {
+	ID      int
+	Ordered bool
+}
+
+type relationship struct {
+	ID, Type, Target string
+	External         bool
+}
+
+type mediaPart struct {
+	Name string // e.g. "image1.png"
+	Data []byte
+}
+
+func newDocBuilder(ctx context.Context, lang string) *docBuilder {
+	return &docBuilder{ctx: ctx, lang: lang, nextRID: 1}
+}
+
+func (b *docBuilder) addRel(relType, target string, external bool) string {
+	id := fmt.Sprintf("rId%d", b.nextRID)
+	b.nextRID++
+	b.rels = append(b.rels, relationship{ID: id, Type: relType, Target: target, External: external})
+	return id
+}
+
+func (b *docBuilder) renderTitle(title string) {
+	b.body.WriteString(`<w:p><w:pPr><w:pStyle w:val="Title"/></w:pPr><w:r><w:rPr><w:b/><w:sz w:val="48"/></w:rPr><w:t xml:space="preserve">`)
+	b.body.WriteString(xmlEscape(title))
+	b.body.WriteString(`</w:t></w:r></w:p>`)
+}
+
+// renderTOC writes one linked entry per article, pointing at the bookmark
+// renderBookmarkedTitle will place at that article's heading.
+func (b *docBuilder) renderTOC(articles []render.Article) {
+	for i, a := range articles {
+		bookmark := fmt.Sprintf("article%d", i)
+		fmt.Fprintf(&b.body, `<w:p><w:pPr><w:jc w:val="both"/></w:pPr><w:r><w:fldChar w:fldCharType="begin"/></w:r>`)
+		fmt.Fprintf(&b.body, `<w:r><w:instrText xml:space="preserve"> HYPERLINK \l "%s" </w:instrText></w:r>`, bookmark)
+		fmt.Fprintf(&b.body, `<w:r><w:fldChar w:fldCharType="separate"/></w:r>`)
+		fmt.Fprintf(&b.body, `<w:r><w:rPr><w:rStyle w:val="Hyperlink"/></w:rPr><w:t xml:space="preserve">%s</w:t></w:r>`, xmlEscape(a.Title))
+		b.body.WriteString(`<w:r><w:fldChar w:fldCharType="end"/></w:r></w:p>`)
+	}
+}
+
+func (b *docBuilder) renderBookmarkedTitle(index int, title string) {
+	bookmark := fmt.Sprintf("article%d", index)
+	fmt.Fprintf(&b.body, `<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:bookmarkStart w:id="%d" w:name="%s"/>`, index, bookmark)
+	fmt.Fprintf(&b.body, `<w:r><w:t xml:space="preserve">%s</w:t></w:r><w:bookmarkEnd w:id="%d"/></w:p>`, xmlEscape(title), index)
+}
+
+func (b *docBuilder) renderNode(n wikitext.Node) {
+	if n.Kind != wikitext.ListItem {
+		b.breakList()
+	}
+	switch n.Kind {
+	case wikitext.Heading:
+		b.renderParagraph(fmt.Sprintf("Heading%d", clamp(n.Level, 1, 6)), n.Runs, 0, 0)
+	case wikitext.Paragraph:
+		b.renderParagraph("", n.Runs, 0, 0)
+	case wikitext.ListItem:
+		b.renderListItem(n)
+	case wikitext.Table:
+		b.renderTable(n)
+	case wikitext.Image:
+		b.renderImage(n.Image)
+	}
+}
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+// renderParagraph writes a <w:p> with an optional paragraph style and runs.
+func (b *docBuilder) renderParagraph(style string, runs []wikitext.Run, numID, ilvl int) {
+	b.body.WriteString(`<w:p><w:pPr>`)
+	if style != "" {
+		fmt.Fprintf(&b.body, `<w:pStyle w:val="%s"/>`, style)
+	} else {
+		b.body.WriteString(`<w:jc w:val="both"/>`)
+	}
+	if numID > 0 {
+		fmt.Fprintf(&b.body, `<w:numPr><w:ilvl w:val="%d"/><w:numId w:val="%d"/></w:numPr>`, ilvl, numID)
+	}
+	b.body.WriteString(`</w:pPr>`)
+	b.renderRuns(runs)
+	b.body.WriteString(`</w:p>`)
+}
+
+// renderListItem writes a list item, starting a fresh numId (and so a
+// fresh numbered/bulleted sequence) whenever this item doesn't continue
+// the immediately preceding run of same-Ordered list items.
+func (b *docBuilder) renderListItem(n wikitext.Node) {
+	if b.curListNumID == 0 || b.curOrdered != n.Ordered {
+		b.curListNumID = b.newNum(n.Ordered)
+		b.curOrdered = n.Ordered
+	}
+	ilvl := n.Level - 1
+	if ilvl < 0 {
+		ilvl = 0
+	}
+	b.renderParagraph("ListParagraph", n.Runs, b.curListNumID, ilvl)
+}
+
+// breakList ends the run of list items numId currently applies to, so
+// the next ListItem node (even if it's the same Ordered kind) starts a
+// new sequence numbered from 1 instead of continuing this one.
+func (b *docBuilder) breakList() {
+	b.curListNumID = 0
+}
+
+// newNum mints a fresh numbering.xml <w:num> bound to the bulleted or
+// decimal abstractNum, so a new contiguous run of list items restarts
+// its own numbering instead of continuing wherever an earlier,
+// unrelated run of the same kind left off.
+func (b *docBuilder) newNum(ordered bool) int {
+	id := len(b.nums) + 1
+	b.nums = append(b.nums, numInstance{ID: id, Ordered: ordered})
+	return id
+}
+
+func (b *docBuilder) renderRuns(runs []wikitext.Run) {
+	for _, r := range runs {
+		if r.Text == "" {
+			continue
+		}
+		if r.Link != "" {
+			rid := b.addRel("http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink", r.Link, true)
+			fmt.Fprintf(&b.body, `<w:hyperlink r:id="%s"><w:r><w:rPr><w:rStyle w:val="Hyperlink"/></w:rPr><w:t xml:space="preserve">%s</w:t></w:r></w:hyperlink>`, rid, xmlEscape(r.Text))
+			continue
+		}
+		b.body.WriteString(`<w:r><w:rPr><w:sz w:val="24"/>`)
+		if r.Bold {
+			b.body.WriteString(`<w:b/>`)
+		}
+		if r.Italic {
+			b.body.WriteString(`<w:i/>`)
+		}
+		b.body.WriteString(`</w:rPr><w:t xml:space="preserve">`)
+		b.body.WriteString(xmlEscape(r.Text))
+		b.body.WriteString(`</w:t></w:r>`)
+	}
+}
+
+func (b *docBuilder) renderTable(n wikitext.Node) {
+	if len(n.Rows) == 0 {
+		return
+	}
+	cols := 0
+	for _, row := range n.Rows {
+		if len(row) > cols {
+			cols = len(row)
+		}
+	}
+	b.body.WriteString(`<w:tbl><w:tblPr><w:tblStyle w:val="TableGrid"/><w:tblW w:w="0" w:type="auto"/></w:tblPr><w:tblGrid>`)
+	for c := 0; c < cols; c++ {
+		b.body.WriteString(`<w:gridCol/>`)
+	}
+	b.body.WriteString(`</w:tblGrid>`)
+	for _, row := range n.Rows {
+		b.body.WriteString(`<w:tr>`)
+		for _, cell := range row {
+			b.body.WriteString(`<w:tc><w:tcPr/><w:p><w:r><w:rPr><w:sz w:val="24"/></w:rPr><w:t xml:space="preserve">`)
+			b.body.WriteString(xmlEscape(cell))
+			b.body.WriteString(`</w:t></w:r></w:p></w:tc>`)
+		}
+		b.body.WriteString(`</w:tr>`)
+	}
+	b.body.WriteString(`</w:tbl>`)
+}
+
+// renderImage downloads a [[File:...]] target via Special:FilePath and
+// embeds it as a word/media part, or skips it on failure.
+func (b *docBuilder) renderImage(fileTitle string) {
+	data, ext, err := downloadImage(b.ctx, b.lang, fileTitle)
+	if err != nil {
+		return
+	}
+	name := fmt.Sprintf("image%d%s", len(b.media)+1, ext)
+	b.media = append(b.media, mediaPart{Name: name, Data: data})
+	rid := b.addRel("http://schemas.openxmlformats.org/officeDocument/2006/relationships/image", "media/"+name, false)
+
+	b.body.WriteString(`<w:p><w:r><w:drawing><wp:inline xmlns:wp="http://schemas.openxmlformats.org/drawingml/2006/wordprocessingDrawing">`)
+	b.body.WriteString(`<wp:extent cx="3000000" cy="2000000"/>`)
+	fmt.Fprintf(&b.body, `<a:graphic xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"><a:graphicData uri="http://schemas.openxmlformats.org/drawingml/2006/picture"><pic:pic xmlns:pic="http://schemas.openxmlformats.org/drawingml/2006/picture"><pic:blipFill><a:blip r:embed="%s"/><a:stretch><a:fillRect/></a:stretch></pic:blipFill><pic:spPr><a:xfrm><a:ext cx="3000000" cy="2000000"/></a:xfrm><a:prstGeom prst="rect"><a:avLst/></a:prstGeom></pic:spPr></pic:pic></a:graphicData></a:graphic>`, rid)
+	b.body.WriteString(`</wp:inline></w:drawing></w:r></w:p>`)
+}
+
+// downloadImage fetches a MediaWiki file via wiki.FetchFile (so it's
+// subject to the same rate limiting, retry and User-Agent policy as every
+// other request this tool makes) and returns the raw bytes plus a file
+// extension derived from the title.
+func downloadImage(ctx context.Context, lang, fileTitle string) ([]byte, string, error) {
+	data, err := wiki.FetchFile(ctx, lang, fileTitle)
+	if err != nil {
+		return nil, "", err
+	}
+	name := strings.TrimPrefix(fileTitle, "File:")
+	name = strings.TrimPrefix(name, "Image:")
+	return data, strings.ToLower(filepath.Ext(name)), nil
+}
+
+func writeDocx(path string, b *docBuilder) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create file: %w", err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	defer zw.Close()
+
+	addFile := func(name string, content []byte) error {
+		w, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(content)
+		return err
+	}
+
+	if err := addFile("[Content_Types].xml", []byte(contentTypesXML(b.media))); err != nil {
+		return fmt.Errorf("write [Content_Types].xml: %w", err)
+	}
+	if err := addFile("_rels/.rels", []byte(rootRelsXML)); err != nil {
+		return fmt.Errorf("write _rels/.rels: %w", err)
+	}
+	if err := addFile("word/document.xml", []byte(buildDocumentXML(b.body.String()))); err != nil {
+		return fmt.Errorf("write word/document.xml: %w", err)
+	}
+	if err := addFile("word/numbering.xml", []byte(numberingXML(b.nums))); err != nil {
+		return fmt.Errorf("write word/numbering.xml: %w", err)
+	}
+	if err := addFile("word/_rels/document.xml.rels", []byte(documentRelsXML(b.rels))); err != nil {
+		return fmt.Errorf("write word/_rels/document.xml.rels: %w", err)
+	}
+	for _, m := range b.media {
+		if err := addFile("word/media/"+m.Name, m.Data); err != nil {
+			return fmt.Errorf("write word/media/%s: %w", m.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func buildDocumentXML(body string) string {
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`)
+	sb.WriteString(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">`)
+	sb.WriteString(`<w:body>`)
+	sb.WriteString(body)
+	sb.WriteString(`<w:sectPr><w:pgSz w:w="12240" w:h="15840"/><w:pgMar w:top="1440" w:right="1440" w:bottom="1440" w:left="1440" w:header="708" w:footer="708" w:gutter="0"/></w:sectPr>`)
+	sb.WriteString(`</w:body></w:document>`)
+	return sb.String()
+}
+
+func documentRelsXML(rels []relationship) string {
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`)
+	sb.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	sb.WriteString(`<Relationship Id="rIdNumbering" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/numbering" Target="numbering.xml"/>`)
+	for _, r := range rels {
+		mode := ""
+		if r.External {
+			mode = ` TargetMode="External"`
+		}
+		fmt.Fprintf(&sb, `<Relationship Id="%s" Type="%s" Target="%s"%s/>`, r.ID, r.Type, xmlEscape(r.Target), mode)
+	}
+	sb.WriteString(`</Relationships>`)
+	return sb.String()
+}
+
+func contentTypesXML(media []mediaPart) string {
+	exts := map[string]string{}
+	for _, m := range media {
+		ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(m.Name)), ".")
+		switch ext {
+		case "jpg", "jpeg":
+			exts["jpeg"] = "image/jpeg"
+		case "png":
+			exts["png"] = "image/png"
+		case "gif":
+			exts["gif"] = "image/gif"
+		case "svg":
+			exts["svg"] = "image/svg+xml"
+		default:
+			if ext != "" {
+				exts[ext] = "application/octet-stream"
+			}
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`)
+	sb.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	sb.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	sb.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	for ext, ct := range exts {
+		fmt.Fprintf(&sb, `<Default Extension="%s" ContentType="%s"/>`, ext, ct)
+	}
+	sb.WriteString(`<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>`)
+	sb.WriteString(`<Override PartName="/word/numbering.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.numbering+xml"/>`)
+	sb.WriteString(`</Types>`)
+	return sb.String()
+}
+
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	if err := xml.EscapeText(&buf, []byte(s)); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+const rootRelsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+  <Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>
+</Relationships>`
+
+// bulletAbstractNumID and decimalAbstractNumID are the abstractNumId
+// values of the two level definitions numberingXML always declares;
+// every num instance it writes points at one or the other.
+const (
+	bulletAbstractNumID  = 0
+	decimalAbstractNumID = 1
+)
+
+// numberingXML declares the bulleted and decimal level formatting once,
+// then emits one <w:num> per entry in nums — each with its own numId and
+// a startOverride on every level, so every contiguous run of list items
+// docBuilder recorded gets an independent sequence that restarts at 1,
+// instead of every list in the document sharing one running count.
+func numberingXML(nums []numInstance) string {
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`)
+	sb.WriteString(`<w:numbering xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">`)
+	fmt.Fprintf(&sb, `<w:abstractNum w:abstractNumId="%d">`, bulletAbstractNumID)
+	sb.WriteString(`<w:lvl w:ilvl="0"><w:numFmt w:val="bullet"/><w:lvlText w:val="&#8226;"/><w:pPr><w:ind w:left="720" w:hanging="360"/></w:pPr></w:lvl>`)
+	sb.WriteString(`<w:lvl w:ilvl="1"><w:numFmt w:val="bullet"/><w:lvlText w:val="&#9702;"/><w:pPr><w:ind w:left="1440" w:hanging="360"/></w:pPr></w:lvl>`)
+	sb.WriteString(`<w:lvl w:ilvl="2"><w:numFmt w:val="bullet"/><w:lvlText w:val="&#9642;"/><w:pPr><w:ind w:left="2160" w:hanging="360"/></w:pPr></w:lvl>`)
+	sb.WriteString(`</w:abstractNum>`)
+	fmt.Fprintf(&sb, `<w:abstractNum w:abstractNumId="%d">`, decimalAbstractNumID)
+	sb.WriteString(`<w:lvl w:ilvl="0"><w:numFmt w:val="decimal"/><w:lvlText w:val="%1."/><w:pPr><w:ind w:left="720" w:hanging="360"/></w:pPr></w:lvl>`)
+	sb.WriteString(`<w:lvl w:ilvl="1"><w:numFmt w:val="decimal"/><w:lvlText w:val="%2."/><w:pPr><w:ind w:left="1440" w:hanging="360"/></w:pPr></w:lvl>`)
+	sb.WriteString(`<w:lvl w:ilvl="2"><w:numFmt w:val="decimal"/><w:lvlText w:val="%3."/><w:pPr><w:ind w:left="2160" w:hanging="360"/></w:pPr></w:lvl>`)
+	sb.WriteString(`</w:abstractNum>`)
+
+	for _, n := range nums {
+		abstractID := bulletAbstractNumID
+		if n.Ordered {
+			abstractID = decimalAbstractNumID
+		}
+		fmt.Fprintf(&sb, `<w:num w:numId="%d"><w:abstractNumId w:val="%d"/>`, n.ID, abstractID)
+		for ilvl := 0; ilvl < 3; ilvl++ {
+			fmt.Fprintf(&sb, `<w:lvlOverride w:ilvl="%d"><w:startOverride w:val="1"/></w:lvlOverride>`, ilvl)
+		}
+		sb.WriteString(`</w:num>`)
+	}
+
+	sb.WriteString(`</w:numbering>`)
+	return sb.String()
+}
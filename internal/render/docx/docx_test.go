@@ -0,0 +1,87 @@
+package docx
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/w0ikid/wiki2docx/internal/wikitext"
+)
+
+// numIDsInBody returns every w:numId referenced in b.body, in the order
+// they appear.
+func numIDsInBody(body string) []string {
+	var ids []string
+	const marker = `w:numId w:val="`
+	for {
+		idx := strings.Index(body, marker)
+		if idx == -1 {
+			break
+		}
+		body = body[idx+len(marker):]
+		end := strings.IndexByte(body, '"')
+		ids = append(ids, body[:end])
+		body = body[end:]
+	}
+	return ids
+}
+
+func TestRenderListItemRestartsNumberingAfterBreak(t *testing.T) {
+	b := newDocBuilder(context.Background(), "en")
+
+	// First numbered list: two items.
+	b.renderNode(wikitext.Node{Kind: wikitext.ListItem, Level: 1, Ordered: true, Runs: []wikitext.Run{{Text: "a"}}})
+	b.renderNode(wikitext.Node{Kind: wikitext.ListItem, Level: 1, Ordered: true, Runs: []wikitext.Run{{Text: "b"}}})
+
+	// A paragraph breaks the list.
+	b.renderNode(wikitext.Node{Kind: wikitext.Paragraph, Runs: []wikitext.Run{{Text: "interrupting paragraph"}}})
+
+	// Second, unrelated numbered list: should NOT continue the first's count.
+	b.renderNode(wikitext.Node{Kind: wikitext.ListItem, Level: 1, Ordered: true, Runs: []wikitext.Run{{Text: "c"}}})
+	b.renderNode(wikitext.Node{Kind: wikitext.ListItem, Level: 1, Ordered: true, Runs: []wikitext.Run{{Text: "d"}}})
+
+	ids := numIDsInBody(b.body.String())
+	if len(ids) != 4 {
+		t.Fatalf("got %d numId references, want 4: %v", len(ids), ids)
+	}
+	if ids[0] != ids[1] {
+		t.Fatalf("first list's items got different numIds: %v", ids[:2])
+	}
+	if ids[2] != ids[3] {
+		t.Fatalf("second list's items got different numIds: %v", ids[2:])
+	}
+	if ids[0] == ids[2] {
+		t.Fatalf("second list reused the first list's numId %q; it should have restarted at a fresh one", ids[0])
+	}
+
+	if len(b.nums) != 2 {
+		t.Fatalf("got %d num instances, want 2 (one per list run): %+v", len(b.nums), b.nums)
+	}
+
+	xml := numberingXML(b.nums)
+	if strings.Count(xml, "<w:num ") != 2 {
+		t.Fatalf("numbering.xml should declare exactly 2 <w:num> instances:\n%s", xml)
+	}
+	if strings.Count(xml, "startOverride") == 0 {
+		t.Fatalf("numbering.xml should override each num's start so it restarts at 1:\n%s", xml)
+	}
+}
+
+func TestRenderListItemContinuesSameRun(t *testing.T) {
+	b := newDocBuilder(context.Background(), "en")
+
+	for i := 0; i < 3; i++ {
+		b.renderNode(wikitext.Node{Kind: wikitext.ListItem, Level: 1, Ordered: true, Runs: []wikitext.Run{{Text: "x"}}})
+	}
+
+	ids := numIDsInBody(b.body.String())
+	if len(ids) != 3 {
+		t.Fatalf("got %d numId references, want 3: %v", len(ids), ids)
+	}
+	if ids[0] != ids[1] || ids[1] != ids[2] {
+		t.Fatalf("a single contiguous run of list items should share one numId, got %v", ids)
+	}
+	if len(b.nums) != 1 {
+		t.Fatalf("got %d num instances, want 1 for one contiguous run: %+v", len(b.nums), b.nums)
+	}
+}
@@ -0,0 +1,299 @@
+// Package epub renders one or many articles into an EPUB3 package: a
+// mimetype entry, container.xml, a content.opf manifest/spine, an EPUB3
+// navigation document, and one XHTML chapter per article.
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/w0ikid/wiki2docx/internal/render"
+	"github.com/w0ikid/wiki2docx/internal/wikitext"
+)
+
+func init() {
+	render.Register(Renderer{})
+}
+
+// Renderer implements render.Renderer and render.BundleRenderer for EPUB3.
+type Renderer struct{}
+
+func (Renderer) Name() string { return "epub" }
+
+func (Renderer) Render(ctx context.Context, a render.Article, outDir string) error {
+	return Build([]render.Article{a}, outDir, a.Title)
+}
+
+func (Renderer) RenderBundle(ctx context.Context, articles []render.Article, outDir, bundleName string) error {
+	return Build(articles, outDir, bundleName)
+}
+
+var unsafeChars = regexp.MustCompile(`[\\/:*?"<>| ]+`)
+
+func safeFilename(title string) string {
+	s := unsafeChars.ReplaceAllString(title, "_")
+	s = strings.Trim(s, "_")
+	if len(s) > 200 {
+		s = s[:200]
+	}
+	return s
+}
+
+type chapter struct {
+	ID, Filename, Title string
+}
+
+// Build packs articles into a single EPUB, one chapter per article, with
+// an EPUB3 nav document serving as the table of contents.
+func Build(articles []render.Article, outDir, name string) error {
+	if len(articles) == 0 {
+		return fmt.Errorf("no articles to render")
+	}
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return fmt.Errorf("create output dir: %w", err)
+	}
+
+	chapters := make([]chapter, len(articles))
+	bodies := make([]string, len(articles))
+	for i, a := range articles {
+		lang := a.Lang
+		if lang == "" {
+			lang = wikitext.DefaultLangPrefix
+		}
+		chapters[i] = chapter{
+			ID:       fmt.Sprintf("chap%d", i+1),
+			Filename: fmt.Sprintf("chap%d.xhtml", i+1),
+			Title:    a.Title,
+		}
+		bodies[i] = renderChapterBody(a.Title, a.Wikitext, lang)
+	}
+
+	outPath := filepath.Join(outDir, safeFilename(name)+".epub")
+	return writeEpub(outPath, chapters, bodies)
+}
+
+func writeEpub(path string, chapters []chapter, bodies []string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create file: %w", err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	defer zw.Close()
+
+	// The mimetype entry must be the first file in the archive and stored
+	// uncompressed so that naive EPUB sniffers can read it directly.
+	mw, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return err
+	}
+	if _, err := mw.Write([]byte("application/epub+zip")); err != nil {
+		return err
+	}
+
+	addFile := func(name string, content string) error {
+		w, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write([]byte(content))
+		return err
+	}
+
+	if err := addFile("META-INF/container.xml", containerXML); err != nil {
+		return fmt.Errorf("write container.xml: %w", err)
+	}
+	if err := addFile("OEBPS/content.opf", contentOPF(chapters)); err != nil {
+		return fmt.Errorf("write content.opf: %w", err)
+	}
+	if err := addFile("OEBPS/nav.xhtml", navXHTML(chapters)); err != nil {
+		return fmt.Errorf("write nav.xhtml: %w", err)
+	}
+	for i, ch := range chapters {
+		if err := addFile("OEBPS/"+ch.Filename, bodies[i]); err != nil {
+			return fmt.Errorf("write %s: %w", ch.Filename, err)
+		}
+	}
+
+	return nil
+}
+
+func renderChapterBody(title, wikitextSrc, lang string) string {
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8"?>`)
+	sb.WriteString(`<!DOCTYPE html>`)
+	sb.WriteString(`<html xmlns="http://www.w3.org/1999/xhtml"><head><meta charset="utf-8"/><title>`)
+	sb.WriteString(htmlEscape(title))
+	sb.WriteString(`</title></head><body>`)
+	fmt.Fprintf(&sb, `<h1>%s</h1>`, htmlEscape(title))
+	writeNodes(&sb, wikitext.ParseBlocksForLang(wikitextSrc, lang), lang)
+	sb.WriteString(`</body></html>`)
+	return sb.String()
+}
+
+// writeNodes walks nodes in order, grouping each consecutive run of
+// same-Ordered, same-Level ListItem nodes into one enclosing <ul>/<ol>
+// instead of wrapping every item in its own list element, which would
+// otherwise reset numbering at every item and add an extra blank-line
+// gap between every bullet.
+func writeNodes(sb *strings.Builder, nodes []wikitext.Node, lang string) {
+	i := 0
+	for i < len(nodes) {
+		n := nodes[i]
+		if n.Kind != wikitext.ListItem {
+			writeNode(sb, n, lang)
+			i++
+			continue
+		}
+
+		tag := "ul"
+		if n.Ordered {
+			tag = "ol"
+		}
+		fmt.Fprintf(sb, "<%s>", tag)
+		for i < len(nodes) && nodes[i].Kind == wikitext.ListItem && nodes[i].Ordered == n.Ordered && nodes[i].Level == n.Level {
+			sb.WriteString("<li>")
+			writeRuns(sb, nodes[i].Runs)
+			sb.WriteString("</li>")
+			i++
+		}
+		fmt.Fprintf(sb, "</%s>", tag)
+	}
+}
+
+func writeNode(sb *strings.Builder, n wikitext.Node, lang string) {
+	switch n.Kind {
+	case wikitext.Heading:
+		tag := fmt.Sprintf("h%d", clamp(n.Level, 1, 6))
+		fmt.Fprintf(sb, "<%s>", tag)
+		writeRuns(sb, n.Runs)
+		fmt.Fprintf(sb, "</%s>", tag)
+
+	case wikitext.Paragraph:
+		sb.WriteString("<p>")
+		writeRuns(sb, n.Runs)
+		sb.WriteString("</p>")
+
+	case wikitext.Table:
+		writeTable(sb, n)
+
+	case wikitext.Image:
+		fmt.Fprintf(sb, `<p><img alt="%s" src="%s"/></p>`, htmlEscape(n.Image), htmlEscape(imageURL(n.Image, lang)))
+	}
+}
+
+func writeRuns(sb *strings.Builder, runs []wikitext.Run) {
+	for _, r := range runs {
+		text := htmlEscape(r.Text)
+		if r.Bold {
+			text = "<b>" + text + "</b>"
+		}
+		if r.Italic {
+			text = "<i>" + text + "</i>"
+		}
+		if r.Link != "" {
+			text = fmt.Sprintf(`<a href="%s">%s</a>`, htmlEscape(r.Link), text)
+		}
+		sb.WriteString(text)
+	}
+}
+
+func writeTable(sb *strings.Builder, n wikitext.Node) {
+	if len(n.Rows) == 0 {
+		return
+	}
+	sb.WriteString("<table>")
+	for _, row := range n.Rows {
+		sb.WriteString("<tr>")
+		for _, cell := range row {
+			fmt.Fprintf(sb, "<td>%s</td>", htmlEscape(cell))
+		}
+		sb.WriteString("</tr>")
+	}
+	sb.WriteString("</table>")
+}
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+// imageURL points at the live Special:FilePath redirect, since this
+// renderer links out to images rather than embedding them as epub parts.
+func imageURL(fileTitle, lang string) string {
+	name := strings.TrimPrefix(fileTitle, "File:")
+	name = strings.TrimPrefix(name, "Image:")
+	return fmt.Sprintf("https://%s.wikipedia.org/wiki/Special:FilePath/%s", lang, strings.ReplaceAll(name, " ", "_"))
+}
+
+func htmlEscape(s string) string {
+	var buf bytes.Buffer
+	if err := xml.EscapeText(&buf, []byte(s)); err != nil {
+		return s
+	}
+	return buf.String()
+}
+
+func contentOPF(chapters []chapter) string {
+	var manifest, spine strings.Builder
+	for _, ch := range chapters {
+		fmt.Fprintf(&manifest, `<item id="%s" href="%s" media-type="application/xhtml+xml"/>`, ch.ID, ch.Filename)
+		fmt.Fprintf(&spine, `<itemref idref="%s"/>`, ch.ID)
+	}
+
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="bookid">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:identifier id="bookid">urn:uuid:wiki2docx-%d</dc:identifier>
+    <dc:title>%s</dc:title>
+    <dc:language>en</dc:language>
+    <meta property="dcterms:modified">%s</meta>
+  </metadata>
+  <manifest>
+    <item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+    %s
+  </manifest>
+  <spine>
+    %s
+  </spine>
+</package>`, time.Now().UnixNano(), htmlEscape(chapters[0].Title), time.Now().UTC().Format("2006-01-02T15:04:05Z"), manifest.String(), spine.String())
+}
+
+func navXHTML(chapters []chapter) string {
+	var items strings.Builder
+	for _, ch := range chapters {
+		fmt.Fprintf(&items, `<li><a href="%s">%s</a></li>`, ch.Filename, htmlEscape(ch.Title))
+	}
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">
+<head><meta charset="utf-8"/><title>Table of Contents</title></head>
+<body>
+  <nav epub:type="toc" id="toc">
+    <h1>Table of Contents</h1>
+    <ol>%s</ol>
+  </nav>
+</body>
+</html>`, items.String())
+}
+
+const containerXML = `<?xml version="1.0" encoding="UTF-8"?>
+<container xmlns="urn:oasis:names:tc:opendocument:xmlns:container" version="1.0">
+  <rootfiles>
+    <rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>`
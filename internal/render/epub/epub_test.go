@@ -0,0 +1,42 @@
+package epub
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/w0ikid/wiki2docx/internal/wikitext"
+)
+
+func TestWriteNodesGroupsConsecutiveListItems(t *testing.T) {
+	var sb strings.Builder
+	nodes := []wikitext.Node{
+		{Kind: wikitext.ListItem, Level: 1, Ordered: true, Runs: []wikitext.Run{{Text: "a"}}},
+		{Kind: wikitext.ListItem, Level: 1, Ordered: true, Runs: []wikitext.Run{{Text: "b"}}},
+		{Kind: wikitext.ListItem, Level: 1, Ordered: true, Runs: []wikitext.Run{{Text: "c"}}},
+	}
+	writeNodes(&sb, nodes, "en")
+
+	got := sb.String()
+	want := "<ol><li>a</li><li>b</li><li>c</li></ol>"
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestWriteNodesStartsNewListOnKindOrLevelChange(t *testing.T) {
+	var sb strings.Builder
+	nodes := []wikitext.Node{
+		{Kind: wikitext.ListItem, Level: 1, Ordered: true, Runs: []wikitext.Run{{Text: "a"}}},
+		{Kind: wikitext.ListItem, Level: 1, Ordered: false, Runs: []wikitext.Run{{Text: "b"}}},
+		{Kind: wikitext.ListItem, Level: 2, Ordered: false, Runs: []wikitext.Run{{Text: "n"}}},
+		{Kind: wikitext.Paragraph, Runs: []wikitext.Run{{Text: "p"}}},
+		{Kind: wikitext.ListItem, Level: 1, Ordered: true, Runs: []wikitext.Run{{Text: "c"}}},
+	}
+	writeNodes(&sb, nodes, "en")
+
+	got := sb.String()
+	want := "<ol><li>a</li></ol><ul><li>b</li></ul><ul><li>n</li></ul><p>p</p><ol><li>c</li></ol>"
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
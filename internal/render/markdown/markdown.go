@@ -0,0 +1,140 @@
+// Package markdown renders an article's wikitext into a plain .md file.
+package markdown
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/w0ikid/wiki2docx/internal/render"
+	"github.com/w0ikid/wiki2docx/internal/wikitext"
+)
+
+func init() {
+	render.Register(Renderer{})
+}
+
+// Renderer implements render.Renderer for Markdown.
+type Renderer struct{}
+
+func (Renderer) Name() string { return "md" }
+
+func (Renderer) Render(ctx context.Context, a render.Article, outDir string) error {
+	return Build(a.Title, a.Wikitext, outDir, a.Lang)
+}
+
+var unsafeChars = regexp.MustCompile(`[\\/:*?"<>| ]+`)
+
+func safeFilename(title string) string {
+	s := unsafeChars.ReplaceAllString(title, "_")
+	s = strings.Trim(s, "_")
+	if len(s) > 200 {
+		s = s[:200]
+	}
+	return s
+}
+
+// Build parses wikitext and writes it out as a Markdown file.
+func Build(title, wikitextSrc, outDir, lang string) error {
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return fmt.Errorf("create output dir: %w", err)
+	}
+	if lang == "" {
+		lang = wikitext.DefaultLangPrefix
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n", title)
+	for _, n := range wikitext.ParseBlocksForLang(wikitextSrc, lang) {
+		writeNode(&sb, n, lang)
+	}
+
+	filename := safeFilename(title) + ".md"
+	outPath := filepath.Join(outDir, filename)
+	if err := os.WriteFile(outPath, []byte(sb.String()), 0o644); err != nil {
+		return fmt.Errorf("save markdown: %w", err)
+	}
+	return nil
+}
+
+func writeNode(sb *strings.Builder, n wikitext.Node, lang string) {
+	switch n.Kind {
+	case wikitext.Heading:
+		sb.WriteString(strings.Repeat("#", clamp(n.Level, 1, 6)))
+		sb.WriteByte(' ')
+		writeRuns(sb, n.Runs)
+		sb.WriteString("\n\n")
+
+	case wikitext.Paragraph:
+		writeRuns(sb, n.Runs)
+		sb.WriteString("\n\n")
+
+	case wikitext.ListItem:
+		sb.WriteString(strings.Repeat("  ", n.Level-1))
+		if n.Ordered {
+			sb.WriteString("1. ")
+		} else {
+			sb.WriteString("- ")
+		}
+		writeRuns(sb, n.Runs)
+		sb.WriteString("\n")
+
+	case wikitext.Table:
+		writeTable(sb, n)
+
+	case wikitext.Image:
+		fmt.Fprintf(sb, "![%s](%s)\n\n", n.Image, imageURL(n.Image, lang))
+	}
+}
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+func writeRuns(sb *strings.Builder, runs []wikitext.Run) {
+	for _, r := range runs {
+		text := r.Text
+		switch {
+		case r.Bold && r.Italic:
+			text = "***" + text + "***"
+		case r.Bold:
+			text = "**" + text + "**"
+		case r.Italic:
+			text = "_" + text + "_"
+		}
+		if r.Link != "" {
+			text = fmt.Sprintf("[%s](%s)", r.Text, r.Link)
+		}
+		sb.WriteString(text)
+	}
+}
+
+func writeTable(sb *strings.Builder, n wikitext.Node) {
+	if len(n.Rows) == 0 {
+		return
+	}
+	header := n.Rows[0]
+	sb.WriteString("| " + strings.Join(header, " | ") + " |\n")
+	sb.WriteString("|" + strings.Repeat(" --- |", len(header)) + "\n")
+	for _, row := range n.Rows[1:] {
+		sb.WriteString("| " + strings.Join(row, " | ") + " |\n")
+	}
+	sb.WriteString("\n")
+}
+
+// imageURL points at the live Special:FilePath redirect for the image,
+// since Markdown output has no media part to embed bytes into.
+func imageURL(fileTitle, lang string) string {
+	name := strings.TrimPrefix(fileTitle, "File:")
+	name = strings.TrimPrefix(name, "Image:")
+	return fmt.Sprintf("https://%s.wikipedia.org/wiki/Special:FilePath/%s", lang, strings.ReplaceAll(name, " ", "_"))
+}
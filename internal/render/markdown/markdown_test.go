@@ -0,0 +1,36 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/w0ikid/wiki2docx/internal/wikitext"
+)
+
+func TestWriteNodeOrderedListItemsAreContiguous(t *testing.T) {
+	var sb strings.Builder
+	items := []wikitext.Node{
+		{Kind: wikitext.ListItem, Level: 1, Ordered: true, Runs: []wikitext.Run{{Text: "a"}}},
+		{Kind: wikitext.ListItem, Level: 1, Ordered: true, Runs: []wikitext.Run{{Text: "b"}}},
+		{Kind: wikitext.ListItem, Level: 1, Ordered: true, Runs: []wikitext.Run{{Text: "c"}}},
+	}
+	for _, n := range items {
+		writeNode(&sb, n, "en")
+	}
+
+	want := "1. a\n1. b\n1. c\n"
+	if sb.String() != want {
+		t.Fatalf("got %q, want %q", sb.String(), want)
+	}
+}
+
+func TestWriteNodeNestedListIndentsByLevel(t *testing.T) {
+	var sb strings.Builder
+	writeNode(&sb, wikitext.Node{Kind: wikitext.ListItem, Level: 1, Ordered: false, Runs: []wikitext.Run{{Text: "top"}}}, "en")
+	writeNode(&sb, wikitext.Node{Kind: wikitext.ListItem, Level: 2, Ordered: false, Runs: []wikitext.Run{{Text: "nested"}}}, "en")
+
+	want := "- top\n  - nested\n"
+	if sb.String() != want {
+		t.Fatalf("got %q, want %q", sb.String(), want)
+	}
+}
@@ -0,0 +1,295 @@
+// Package pdf renders an article's wikitext as a minimal, dependency-free
+// PDF: plain paginated text using the built-in Helvetica core font. It
+// does not attempt layout beyond word-wrapping and page breaks.
+package pdf
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/w0ikid/wiki2docx/internal/render"
+	"github.com/w0ikid/wiki2docx/internal/wikitext"
+)
+
+func init() {
+	render.Register(Renderer{})
+}
+
+// Renderer implements render.Renderer for PDF.
+type Renderer struct{}
+
+func (Renderer) Name() string { return "pdf" }
+
+func (Renderer) Render(ctx context.Context, a render.Article, outDir string) error {
+	return Build(a.Title, a.Wikitext, outDir, a.Lang)
+}
+
+var unsafeChars = regexp.MustCompile(`[\\/:*?"<>| ]+`)
+
+func safeFilename(title string) string {
+	s := unsafeChars.ReplaceAllString(title, "_")
+	s = strings.Trim(s, "_")
+	if len(s) > 200 {
+		s = s[:200]
+	}
+	return s
+}
+
+const (
+	pageWidth   = 612.0 // US Letter, points
+	pageHeight  = 792.0
+	margin      = 72.0
+	bodySize    = 11.0
+	headingSize = 15.0
+	leading     = 14.0
+	wrapColumns = 92
+)
+
+// Build parses wikitext into plain lines and lays them out across pages
+// of a single PDF file.
+func Build(title, wikitextSrc, outDir, lang string) error {
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return fmt.Errorf("create output dir: %w", err)
+	}
+	if lang == "" {
+		lang = wikitext.DefaultLangPrefix
+	}
+
+	lines := []line{{text: title, size: 20}, {text: "", size: headingSize}}
+	lines = append(lines, renderNodes(wikitext.ParseBlocksForLang(wikitextSrc, lang))...)
+
+	var usableHeight float64 = pageHeight - 2*margin
+	linesPerPage := int(usableHeight / leading)
+	var pages [][]line
+	for len(lines) > 0 {
+		n := linesPerPage
+		if n > len(lines) {
+			n = len(lines)
+		}
+		pages = append(pages, lines[:n])
+		lines = lines[n:]
+	}
+	if len(pages) == 0 {
+		pages = [][]line{{}}
+	}
+
+	filename := safeFilename(title) + ".pdf"
+	outPath := filepath.Join(outDir, filename)
+	if err := os.WriteFile(outPath, buildPDF(pages), 0o644); err != nil {
+		return fmt.Errorf("save pdf: %w", err)
+	}
+	return nil
+}
+
+type line struct {
+	text string
+	size float64
+}
+
+// renderNodes lays out a whole block of nodes, grouping each consecutive
+// run of same-Ordered, same-Level ListItem nodes so ordered lists get an
+// incrementing "1. "/"2. "/"3. " counter instead of repeating "1. " for
+// every item; the counter resets whenever a new list (or a change in
+// indent level) starts.
+func renderNodes(nodes []wikitext.Node) []line {
+	var out []line
+	i := 0
+	for i < len(nodes) {
+		n := nodes[i]
+		if n.Kind != wikitext.ListItem {
+			out = append(out, renderNode(n)...)
+			i++
+			continue
+		}
+
+		counter := 0
+		for i < len(nodes) && nodes[i].Kind == wikitext.ListItem && nodes[i].Ordered == n.Ordered && nodes[i].Level == n.Level {
+			counter++
+			out = append(out, renderListItem(nodes[i], counter)...)
+			i++
+		}
+	}
+	return out
+}
+
+func renderListItem(n wikitext.Node, counter int) []line {
+	bullet := "- "
+	if n.Ordered {
+		bullet = fmt.Sprintf("%d. ", counter)
+	}
+	return wrapLines(strings.Repeat("  ", n.Level-1)+bullet+runsToPlain(n.Runs), bodySize)
+}
+
+func renderNode(n wikitext.Node) []line {
+	switch n.Kind {
+	case wikitext.Heading:
+		return append(wrapLines(runsToPlain(n.Runs), headingSize), line{text: "", size: bodySize})
+
+	case wikitext.Paragraph:
+		return append(wrapLines(runsToPlain(n.Runs), bodySize), line{text: "", size: bodySize})
+
+	case wikitext.Table:
+		var out []line
+		for _, row := range n.Rows {
+			out = append(out, wrapLines(strings.Join(row, " | "), bodySize)...)
+		}
+		return append(out, line{text: "", size: bodySize})
+
+	case wikitext.Image:
+		return []line{{text: "[image: " + n.Image + "]", size: bodySize}}
+	}
+	return nil
+}
+
+func runsToPlain(runs []wikitext.Run) string {
+	var sb strings.Builder
+	for _, r := range runs {
+		sb.WriteString(r.Text)
+	}
+	return sb.String()
+}
+
+// wrapLines greedily word-wraps s to wrapColumns characters, a crude but
+// dependency-free stand-in for real glyph-width metrics.
+func wrapLines(s string, size float64) []line {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		return []line{{text: "", size: size}}
+	}
+
+	var out []line
+	cur := words[0]
+	for _, w := range words[1:] {
+		if len(cur)+1+len(w) > wrapColumns {
+			out = append(out, line{text: cur, size: size})
+			cur = w
+			continue
+		}
+		cur += " " + w
+	}
+	out = append(out, line{text: cur, size: size})
+	return out
+}
+
+// buildPDF serializes pages of lines into a minimal, valid PDF document.
+func buildPDF(pages [][]line) []byte {
+	var buf strings.Builder
+	buf.WriteString("%PDF-1.4\n")
+
+	// Reserve object numbers: 1=Catalog, 2=Pages, then per page a Page obj
+	// and a Contents obj, then finally the Font obj.
+	catalogNum := 1
+	pagesNum := 2
+	objN := 2
+
+	pageNums := make([]int, len(pages))
+	contentNums := make([]int, len(pages))
+	for i := range pages {
+		objN++
+		pageNums[i] = objN
+		objN++
+		contentNums[i] = objN
+	}
+	fontNum := objN + 1
+
+	offsets := make([]int, fontNum)
+
+	write := func(num int, body string) {
+		offsets[num-1] = buf.Len()
+		buf.WriteString(fmt.Sprintf("%d 0 obj\n%s\nendobj\n", num, body))
+	}
+
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", pageNums[i])
+	}
+	write(catalogNum, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesNum))
+	write(pagesNum, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+
+	for i, p := range pages {
+		stream := contentStream(p)
+		write(pageNums[i], fmt.Sprintf(
+			"<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 %d 0 R >> >> /Contents %d 0 R >>",
+			pagesNum, fmtNum(pageWidth), fmtNum(pageHeight), fontNum, contentNums[i]))
+		write(contentNums[i], fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(stream), stream))
+	}
+	write(fontNum, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>")
+
+	xrefStart := buf.Len()
+	buf.WriteString(fmt.Sprintf("xref\n0 %d\n", fontNum+1))
+	buf.WriteString("0000000000 65535 f \n")
+	for _, off := range offsets {
+		buf.WriteString(fmt.Sprintf("%010d 00000 n \n", off))
+	}
+	buf.WriteString(fmt.Sprintf("trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF", fontNum+1, catalogNum, xrefStart))
+
+	return []byte(buf.String())
+}
+
+func contentStream(lines []line) string {
+	var sb strings.Builder
+	sb.WriteString("BT\n")
+	y := pageHeight - margin
+	for _, l := range lines {
+		fmt.Fprintf(&sb, "/F1 %s Tf\n", fmtNum(l.size))
+		fmt.Fprintf(&sb, "1 0 0 1 %s %s Tm\n", fmtNum(margin), fmtNum(y))
+		fmt.Fprintf(&sb, "(%s) Tj\n", escapePDFString(l.text))
+		y -= leading
+	}
+	sb.WriteString("ET")
+	return sb.String()
+}
+
+// winAnsiHighMap maps the Unicode code points PDF's WinAnsiEncoding places
+// in the 0x80-0x9F byte range (mostly smart punctuation) to their bytes.
+// Everything else outside ASCII maps straight across for 0xA0-0xFF (the
+// Latin-1 supplement, which WinAnsiEncoding mirrors) or falls back to '?'.
+var winAnsiHighMap = map[rune]byte{
+	0x20AC: 0x80, 0x201A: 0x82, 0x0192: 0x83, 0x201E: 0x84,
+	0x2026: 0x85, 0x2020: 0x86, 0x2021: 0x87, 0x02C6: 0x88,
+	0x2030: 0x89, 0x0160: 0x8A, 0x2039: 0x8B, 0x0152: 0x8C,
+	0x017D: 0x8E, 0x2018: 0x91, 0x2019: 0x92, 0x201C: 0x93,
+	0x201D: 0x94, 0x2022: 0x95, 0x2013: 0x96, 0x2014: 0x97,
+	0x02DC: 0x98, 0x2122: 0x99, 0x0161: 0x9A, 0x203A: 0x9B,
+	0x0153: 0x9C, 0x017E: 0x9E, 0x0178: 0x9F,
+}
+
+// encodeWinAnsi transliterates s from Unicode into WinAnsiEncoding bytes,
+// since a PDF string literal under the Helvetica core font is interpreted
+// byte-for-byte as WinAnsi, not UTF-8.
+func encodeWinAnsi(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r < 0x80 || (r >= 0xA0 && r <= 0xFF):
+			out = append(out, byte(r))
+		default:
+			if b, ok := winAnsiHighMap[r]; ok {
+				out = append(out, b)
+			} else {
+				out = append(out, '?')
+			}
+		}
+	}
+	return out
+}
+
+func escapePDFString(s string) string {
+	var sb strings.Builder
+	for _, b := range encodeWinAnsi(s) {
+		if b == '\\' || b == '(' || b == ')' {
+			sb.WriteByte('\\')
+		}
+		sb.WriteByte(b)
+	}
+	return sb.String()
+}
+
+func fmtNum(f float64) string {
+	return strconv.FormatFloat(f, 'f', 2, 64)
+}
@@ -0,0 +1,52 @@
+package pdf
+
+import (
+	"testing"
+
+	"github.com/w0ikid/wiki2docx/internal/wikitext"
+)
+
+func linesText(lines []line) []string {
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		out[i] = l.text
+	}
+	return out
+}
+
+func TestRenderNodesCountsOrderedListItems(t *testing.T) {
+	nodes := []wikitext.Node{
+		{Kind: wikitext.ListItem, Level: 1, Ordered: true, Runs: []wikitext.Run{{Text: "a"}}},
+		{Kind: wikitext.ListItem, Level: 1, Ordered: true, Runs: []wikitext.Run{{Text: "b"}}},
+		{Kind: wikitext.ListItem, Level: 1, Ordered: true, Runs: []wikitext.Run{{Text: "c"}}},
+	}
+	got := linesText(renderNodes(nodes))
+	want := []string{"1. a", "2. b", "3. c"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestRenderNodesResetsCounterOnBreak(t *testing.T) {
+	nodes := []wikitext.Node{
+		{Kind: wikitext.ListItem, Level: 1, Ordered: true, Runs: []wikitext.Run{{Text: "a"}}},
+		{Kind: wikitext.ListItem, Level: 1, Ordered: true, Runs: []wikitext.Run{{Text: "b"}}},
+		{Kind: wikitext.Paragraph, Runs: []wikitext.Run{{Text: "p"}}},
+		{Kind: wikitext.ListItem, Level: 1, Ordered: true, Runs: []wikitext.Run{{Text: "c"}}},
+	}
+	got := linesText(renderNodes(nodes))
+	want := []string{"1. a", "2. b", "p", "", "1. c"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
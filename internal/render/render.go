@@ -0,0 +1,77 @@
+// Package render defines the output-backend abstraction used by
+// wiki2docx: a Renderer turns one parsed Article into files on disk, and
+// concrete formats (docx, md, epub, pdf) register themselves into a
+// shared registry the same way image.RegisterFormat does for codecs.
+package render
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Article is the parsed input handed to every Renderer.
+type Article struct {
+	Title    string
+	Wikitext string
+	Lang     string
+}
+
+// Renderer turns an Article into one or more files under outDir.
+type Renderer interface {
+	// Name is the renderer's -format key, e.g. "docx", "md", "epub", "pdf".
+	Name() string
+	Render(ctx context.Context, article Article, outDir string) error
+}
+
+// BundleRenderer is additionally implemented by renderers that can pack
+// many articles into a single multi-chapter output file, similar to how
+// offline Wikipedia readers package dumps.
+type BundleRenderer interface {
+	Renderer
+	// RenderBundle writes all of articles into one file named bundleName
+	// (without extension) under outDir.
+	RenderBundle(ctx context.Context, articles []Article, outDir, bundleName string) error
+}
+
+var (
+	mu       sync.RWMutex
+	registry = map[string]Renderer{}
+)
+
+// Register makes a Renderer available under its Name(). Renderers
+// register themselves from an init func in their own package.
+func Register(r Renderer) {
+	mu.Lock()
+	defer mu.Unlock()
+	registry[r.Name()] = r
+}
+
+// Get looks up a registered renderer by name.
+func Get(name string) (Renderer, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+	r, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown output format %q (available: %v)", name, names())
+	}
+	return r, nil
+}
+
+// Names returns the registered renderer names, sorted, for flag help text
+// and validation.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return names()
+}
+
+func names() []string {
+	out := make([]string, 0, len(registry))
+	for n := range registry {
+		out = append(out, n)
+	}
+	sort.Strings(out)
+	return out
+}
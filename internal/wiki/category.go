@@ -0,0 +1,194 @@
+package wiki
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// subcategoryNamespace is the MediaWiki namespace ID used to tell
+// subcategories apart from plain pages in a categorymembers listing.
+const (
+	subcategoryNamespace = 14
+)
+
+// GetCategoryMembers returns up to limit article titles belonging to
+// category (e.g. "Category:Physics"). If recursive is true, it also
+// descends into subcategories up to maxDepth levels, tracking visited
+// categories so a cycle in the category graph can't cause infinite
+// recursion. maxDepth is ignored when recursive is false.
+func GetCategoryMembers(category string, recursive bool, maxDepth, limit int) ([]string, error) {
+	return defaultClient.GetCategoryMembers(context.Background(), category, recursive, maxDepth, limit)
+}
+
+// SearchTitles returns up to limit article titles matching query, using
+// MediaWiki's full-text search.
+func SearchTitles(query string, limit int) ([]string, error) {
+	return defaultClient.SearchTitles(context.Background(), query, limit)
+}
+
+// GetCategoryMembers is the Client-bound implementation of the
+// package-level GetCategoryMembers helper; see its doc comment.
+func (c *Client) GetCategoryMembers(ctx context.Context, category string, recursive bool, maxDepth, limit int) ([]string, error) {
+	visited := make(map[string]struct{})
+	var titles []string
+	if err := c.collectCategory(ctx, category, recursive, maxDepth, limit, visited, &titles); err != nil {
+		return titles, err
+	}
+	return titles, nil
+}
+
+// collectCategory walks one category, appending article titles to
+// *titles (stopping once it holds limit of them) and, if recursive,
+// descending into subcategories it hasn't already visited.
+func (c *Client) collectCategory(ctx context.Context, category string, recursive bool, depthLeft, limit int, visited map[string]struct{}, titles *[]string) error {
+	if _, seen := visited[category]; seen {
+		return nil
+	}
+	visited[category] = struct{}{}
+
+	var subcats []string
+	cmcontinue := ""
+	for {
+		if len(*titles) >= limit {
+			return nil
+		}
+
+		apiURL := c.apiURL()
+		params := url.Values{}
+		params.Set("action", "query")
+		params.Set("list", "categorymembers")
+		params.Set("cmtitle", category)
+		params.Set("cmlimit", "500")
+		params.Set("maxlag", "5")
+		params.Set("format", "json")
+		if cmcontinue != "" {
+			params.Set("cmcontinue", cmcontinue)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "GET", apiURL+"?"+params.Encode(), nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := c.do(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		var res struct {
+			Continue struct {
+				CMContinue string `json:"cmcontinue"`
+			} `json:"continue"`
+			Query struct {
+				CategoryMembers []struct {
+					Title string `json:"title"`
+					Ns    int    `json:"ns"`
+				} `json:"categorymembers"`
+			} `json:"query"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&res)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		for _, m := range res.Query.CategoryMembers {
+			if m.Ns == subcategoryNamespace {
+				subcats = append(subcats, m.Title)
+				continue
+			}
+			if len(*titles) >= limit {
+				break
+			}
+			*titles = append(*titles, m.Title)
+		}
+
+		if res.Continue.CMContinue == "" || len(*titles) >= limit {
+			break
+		}
+		cmcontinue = res.Continue.CMContinue
+	}
+
+	if !recursive || depthLeft <= 0 {
+		return nil
+	}
+	for _, sub := range subcats {
+		if len(*titles) >= limit {
+			return nil
+		}
+		if err := c.collectCategory(ctx, sub, recursive, depthLeft-1, limit, visited, titles); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SearchTitles returns up to limit article titles matching query, using
+// MediaWiki's full-text search (list=search).
+func (c *Client) SearchTitles(ctx context.Context, query string, limit int) ([]string, error) {
+	var titles []string
+	sroffset := 0
+
+	for len(titles) < limit {
+		apiURL := c.apiURL()
+		batchSize := limit - len(titles)
+		if batchSize > 500 {
+			batchSize = 500
+		}
+
+		params := url.Values{}
+		params.Set("action", "query")
+		params.Set("list", "search")
+		params.Set("srsearch", query)
+		params.Set("srlimit", fmt.Sprintf("%d", batchSize))
+		params.Set("sroffset", fmt.Sprintf("%d", sroffset))
+		params.Set("maxlag", "5")
+		params.Set("format", "json")
+
+		req, err := http.NewRequestWithContext(ctx, "GET", apiURL+"?"+params.Encode(), nil)
+		if err != nil {
+			return titles, err
+		}
+
+		resp, err := c.do(ctx, req)
+		if err != nil {
+			return titles, err
+		}
+
+		var res struct {
+			Continue struct {
+				SrOffset int `json:"sroffset"`
+			} `json:"continue"`
+			Query struct {
+				Search []struct {
+					Title string `json:"title"`
+				} `json:"search"`
+			} `json:"query"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&res)
+		resp.Body.Close()
+		if err != nil {
+			return titles, err
+		}
+
+		if len(res.Query.Search) == 0 {
+			break
+		}
+		for _, r := range res.Query.Search {
+			titles = append(titles, r.Title)
+			if len(titles) >= limit {
+				break
+			}
+		}
+
+		if res.Continue.SrOffset == 0 {
+			break
+		}
+		sroffset = res.Continue.SrOffset
+	}
+
+	return titles, nil
+}
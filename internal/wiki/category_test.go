@@ -0,0 +1,81 @@
+package wiki
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestGetCategoryMembersHandlesCycle points a Client at a mock MediaWiki
+// API whose category graph has a cycle (Category:A -> Category:B ->
+// Category:A) and checks that recursive collection terminates and still
+// returns every article exactly once, instead of recursing forever.
+func TestGetCategoryMembersHandlesCycle(t *testing.T) {
+	type member struct {
+		Title string `json:"title"`
+		Ns    int    `json:"ns"`
+	}
+	categories := map[string][]member{
+		"Category:A": {
+			{Title: "Article1", Ns: 0},
+			{Title: "Category:B", Ns: subcategoryNamespace},
+		},
+		"Category:B": {
+			{Title: "Article2", Ns: 0},
+			{Title: "Category:A", Ns: subcategoryNamespace}, // cycle back to A
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cmtitle := r.URL.Query().Get("cmtitle")
+		members := categories[cmtitle]
+
+		var res struct {
+			Query struct {
+				CategoryMembers []member `json:"categorymembers"`
+			} `json:"query"`
+		}
+		res.Query.CategoryMembers = members
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(res)
+	}))
+	defer server.Close()
+
+	c := &Client{
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		lang:       "en",
+		apiBase:    server.URL,
+	}
+
+	done := make(chan struct{})
+	var titles []string
+	var err error
+	go func() {
+		titles, err = c.GetCategoryMembers(context.Background(), "Category:A", true, 10, 100)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("GetCategoryMembers did not terminate, likely stuck in a category cycle")
+	}
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]bool{"Article1": true, "Article2": true}
+	if len(titles) != len(want) {
+		t.Fatalf("got titles %v, want exactly %v", titles, want)
+	}
+	for _, title := range titles {
+		if !want[title] {
+			t.Fatalf("unexpected title %q in result %v", title, titles)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+// Package dump reads Wikipedia's "pages-articles-multistream.xml.bz2" dump
+// format, letting callers convert an entire offline dump without hitting
+// the live MediaWiki API.
+package dump
+
+import (
+	"bufio"
+	"compress/bzip2"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Article is a single page decoded from the dump.
+type Article struct {
+	Title     string
+	Namespace int
+	Redirect  bool
+	Text      string
+}
+
+// chunk is one independently-decompressible bz2 stream within the
+// multistream file, spanning [Offset, End) bytes.
+type chunk struct {
+	Offset int64
+	End    int64
+}
+
+// DumpSource streams articles out of a multistream dump using its index
+// to decode chunks concurrently.
+type DumpSource struct {
+	dumpPath    string
+	chunks      []chunk
+	Concurrency int // number of chunks decoded in parallel; defaults to 4
+}
+
+// Open parses the multistream index (pages-articles-multistream-index.txt.bz2)
+// and prepares a DumpSource that reads pages out of dumpPath on demand.
+func Open(dumpPath, indexPath string) (*DumpSource, error) {
+	offsets, err := readIndexOffsets(indexPath)
+	if err != nil {
+		return nil, fmt.Errorf("read dump index: %w", err)
+	}
+
+	info, err := os.Stat(dumpPath)
+	if err != nil {
+		return nil, fmt.Errorf("stat dump file: %w", err)
+	}
+
+	chunks := make([]chunk, 0, len(offsets))
+	for i, off := range offsets {
+		end := info.Size()
+		if i+1 < len(offsets) {
+			end = offsets[i+1]
+		}
+		chunks = append(chunks, chunk{Offset: off, End: end})
+	}
+
+	return &DumpSource{dumpPath: dumpPath, chunks: chunks, Concurrency: 4}, nil
+}
+
+// readIndexOffsets decodes the bz2-compressed index file and returns the
+// sorted, de-duplicated list of byte offsets at which a multistream chunk
+// begins. Each index line has the form "offset:pageid:title".
+func readIndexOffsets(indexPath string) ([]int64, error) {
+	f, err := os.Open(indexPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	seen := make(map[int64]struct{})
+	var offsets []int64
+
+	scanner := bufio.NewScanner(bzip2.NewReader(f))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), ":", 3)
+		if len(parts) < 2 {
+			continue
+		}
+		off, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		if _, ok := seen[off]; ok {
+			continue
+		}
+		seen[off] = struct{}{}
+		offsets = append(offsets, off)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	return offsets, nil
+}
+
+// Visit decodes every chunk of the dump concurrently, calling fn once per
+// non-redirect, main-namespace article. fn may be called from multiple
+// goroutines; it must be safe for concurrent use. Visit stops launching
+// new work once fn returns false, and chunks already in flight stop at
+// their next page without calling fn again.
+func (d *DumpSource) Visit(fn func(*Article) bool) error {
+	concurrency := d.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		stopped  bool
+		firstErr error
+	)
+
+	sem := make(chan struct{}, concurrency)
+	for _, c := range d.chunks {
+		mu.Lock()
+		if stopped {
+			mu.Unlock()
+			break
+		}
+		mu.Unlock()
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(c chunk) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			err := d.visitChunk(c, func(a *Article) bool {
+				mu.Lock()
+				defer mu.Unlock()
+				if stopped {
+					return false
+				}
+				if !fn(a) {
+					stopped = true
+					return false
+				}
+				return true
+			})
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}(c)
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+// PushTo visits the dump and sends each kept article to ch. It does not
+// close ch; the caller owns that, mirroring how main.go fills its
+// titlesCh before starting the worker pool.
+func (d *DumpSource) PushTo(ch chan<- *Article) error {
+	return d.Visit(func(a *Article) bool {
+		ch <- a
+		return true
+	})
+}
+
+// pageXML mirrors the subset of the MediaWiki export schema we care about.
+type pageXML struct {
+	Title    string `xml:"title"`
+	Ns       int    `xml:"ns"`
+	Redirect *struct {
+		Title string `xml:"title,attr"`
+	} `xml:"redirect"`
+	Revision struct {
+		Text string `xml:"text"`
+	} `xml:"revision"`
+}
+
+// visitChunk decompresses a single multistream chunk and decodes each
+// <page> element in it, calling fn for the ones worth keeping.
+func (d *DumpSource) visitChunk(c chunk, fn func(*Article) bool) error {
+	f, err := os.Open(d.dumpPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(c.Offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	// Chunks hold bare <page>...</page> siblings with no enclosing root,
+	// so wrap them in a synthetic one the decoder can parse.
+	body := bzip2.NewReader(io.LimitReader(f, c.End-c.Offset))
+	r := io.MultiReader(strings.NewReader("<mediawiki>"), body, strings.NewReader("</mediawiki>"))
+
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("decode chunk at offset %d: %w", c.Offset, err)
+		}
+
+		se, ok := tok.(xml.StartElement)
+		if !ok || se.Name.Local != "page" {
+			continue
+		}
+
+		var p pageXML
+		if err := dec.DecodeElement(&p, &se); err != nil {
+			return fmt.Errorf("decode page at offset %d: %w", c.Offset, err)
+		}
+
+		if p.Ns != 0 || p.Redirect != nil {
+			continue
+		}
+
+		if !fn(&Article{
+			Title:     p.Title,
+			Namespace: p.Ns,
+			Redirect:  p.Redirect != nil,
+			Text:      p.Revision.Text,
+		}) {
+			return nil
+		}
+	}
+}
@@ -1,11 +1,18 @@
 package wiki
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"math/rand"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
+
+	"golang.org/x/time/rate"
 )
 
 // Article holds the fetched data for a Wikipedia article.
@@ -14,55 +21,162 @@ type Article struct {
 	Content string
 }
 
-// langPrefix is a package-level variable to store the Wikipedia language prefix.
-var langPrefix = "en"
+// maxRetries bounds how many times a single request is retried after a
+// 429/503 response before FetchArticle/GetRandomTitles give up.
+const maxRetries = 5
+
+// Client is a rate-limited MediaWiki API client. The zero value is not
+// usable; construct one with NewClient.
+type Client struct {
+	httpClient *http.Client
+	limiter    *rate.Limiter
+	lang       string
+
+	// apiBase overrides the MediaWiki API endpoint derived from lang.
+	// It exists so tests can point a Client at an httptest.Server
+	// instead of the real Wikipedia API; production code leaves it
+	// empty.
+	apiBase string
+}
+
+// apiURL returns the MediaWiki action API endpoint this client talks to.
+func (c *Client) apiURL() string {
+	if c.apiBase != "" {
+		return c.apiBase
+	}
+	return fmt.Sprintf("https://%s.wikipedia.org/w/api.php", c.lang)
+}
+
+// NewClient returns a Client that talks to the given language edition of
+// Wikipedia, limiting itself to rps requests per second (burst-many back
+// to back) against that host.
+func NewClient(lang string, rps float64, burst int) *Client {
+	var limiter *rate.Limiter
+	if rps > 0 {
+		if burst < 1 {
+			burst = 1
+		}
+		limiter = rate.NewLimiter(rate.Limit(rps), burst)
+	}
+	return &Client{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		limiter:    limiter,
+		lang:       lang,
+	}
+}
 
-// rateLimiter is a channel used for global rate limiting across the package.
-var rateLimiter <-chan time.Time
+// defaultClient backs the package-level FetchArticle/GetRandomTitles
+// helpers so existing callers don't need to manage a Client themselves.
+var defaultClient = NewClient("en", 10, 10)
 
-// SetLanguage sets the Wikipedia language prefix (e.g., "en", "ru").
+// SetLanguage sets the Wikipedia language prefix (e.g., "en", "ru") used
+// by the package-level helpers.
 func SetLanguage(l string) {
-	langPrefix = l
+	defaultClient.lang = l
 }
 
-// SetRateLimit initializes the global rate limiter with the given requests per second.
-// If rps is 0 or less, no rate limiting is applied.
+// SetRateLimit reconfigures the package-level helpers' rate limit to rps
+// requests per second. If rps is 0 or less, no rate limiting is applied.
 func SetRateLimit(rps int) {
 	if rps <= 0 {
-		rateLimiter = nil
+		defaultClient.limiter = nil
 		return
 	}
-	rateLimiter = time.Tick(time.Second / time.Duration(rps))
+	defaultClient.limiter = rate.NewLimiter(rate.Limit(rps), rps)
+}
+
+// FetchArticle retrieves the parsed wikitext of a Wikipedia article by
+// title using the package-level default Client.
+func FetchArticle(title string) (Article, error) {
+	return defaultClient.FetchArticle(context.Background(), title)
+}
+
+// GetRandomTitles returns up to limit unique random Wikipedia article
+// titles using the package-level default Client.
+func GetRandomTitles(limit int) ([]string, error) {
+	return defaultClient.GetRandomTitles(context.Background(), limit)
 }
 
-func wait() {
-	if rateLimiter != nil {
-		<-rateLimiter
+// FetchFile downloads a "File:"/"Image:" page's raw bytes for the given
+// language edition, using the package-level default Client.
+func FetchFile(ctx context.Context, lang, fileTitle string) ([]byte, error) {
+	return defaultClient.FetchFile(ctx, lang, fileTitle)
+}
+
+// do executes req, waiting on the rate limiter first and retrying on
+// 429/503 responses with exponential backoff, honoring a Retry-After
+// header when the server sends one.
+func (c *Client) do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	req.Header.Set("User-Agent", "wiki2docx/1.0 (github.com/w0ikid/wiki2docx)")
+
+	for attempt := 0; ; attempt++ {
+		if c.limiter != nil {
+			if err := c.limiter.Wait(ctx); err != nil {
+				return nil, fmt.Errorf("rate limiter: %w", err)
+			}
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+			return resp, nil
+		}
+		if attempt >= maxRetries {
+			return resp, nil
+		}
+
+		delay := retryDelay(resp.Header.Get("Retry-After"), attempt)
+		resp.Body.Close()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
 	}
 }
 
-// wikiClient is a shared http client for all wiki package requests.
-var wikiClient = &http.Client{}
+// retryDelay parses a Retry-After header (seconds or HTTP-date) if
+// present, falling back to exponential backoff with jitter keyed on attempt.
+func retryDelay(retryAfter string, attempt int) time.Duration {
+	if retryAfter != "" {
+		if secs, err := strconv.Atoi(retryAfter); err == nil {
+			return time.Duration(secs) * time.Second
+		}
+		if t, err := http.ParseTime(retryAfter); err == nil {
+			if d := time.Until(t); d > 0 {
+				return d
+			}
+		}
+	}
+	base := time.Second << uint(attempt)
+	return base + time.Duration(rand.Int63n(int64(time.Second)))
+}
 
-// FetchArticle retrieves the full text of a Wikipedia article by title.
-func FetchArticle(title string) (Article, error) {
-	apiURL := fmt.Sprintf("https://%s.wikipedia.org/w/api.php", langPrefix)
+// FetchArticle retrieves the parsed wikitext of a Wikipedia article by
+// title, so callers can render it into structured output instead of a
+// plaintext extract.
+func (c *Client) FetchArticle(ctx context.Context, title string) (Article, error) {
+	apiURL := c.apiURL()
 	params := url.Values{}
-	params.Set("action", "query")
-	params.Set("prop", "extracts")
-	params.Set("explaintext", "1")
-	params.Set("titles", title)
-	params.Set("format", "json")
+	params.Set("action", "parse")
+	params.Set("page", title)
+	params.Set("prop", "wikitext")
 	params.Set("redirects", "1")
+	params.Set("maxlag", "5")
+	params.Set("format", "json")
 
-	wait()
-	req, err := http.NewRequest("GET", apiURL+"?"+params.Encode(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL+"?"+params.Encode(), nil)
 	if err != nil {
 		return Article{}, err
 	}
-	req.Header.Set("User-Agent", "wiki2docx/1.0 (github.com/w0ikid/wiki2docx)")
 
-	resp, err := wikiClient.Do(req)
+	resp, err := c.do(ctx, req)
 	if err != nil {
 		return Article{}, err
 	}
@@ -73,31 +187,61 @@ func FetchArticle(title string) (Article, error) {
 	}
 
 	var res struct {
-		Query struct {
-			Pages map[string]struct {
-				Title   string `json:"title"`
-				Extract string `json:"extract"`
-			} `json:"pages"`
-		} `json:"query"`
+		Parse struct {
+			Title    string `json:"title"`
+			Wikitext struct {
+				Content string `json:"*"`
+			} `json:"wikitext"`
+		} `json:"parse"`
+		Error *struct {
+			Code string `json:"code"`
+			Info string `json:"info"`
+		} `json:"error"`
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
 		return Article{}, err
 	}
+	if res.Error != nil {
+		return Article{}, fmt.Errorf("article not found: %s (%s)", title, res.Error.Info)
+	}
+
+	return Article{
+		Title:   res.Parse.Title,
+		Content: res.Parse.Wikitext.Content,
+	}, nil
+}
 
-	for _, page := range res.Query.Pages {
-		return Article{
-			Title:   page.Title,
-			Content: page.Extract,
-		}, nil
+// FetchFile downloads the raw bytes of a "File:"/"Image:" page via its
+// Special:FilePath redirect on lang's Wikipedia edition, going through the
+// same rate limiter, Retry-After-aware retry, and User-Agent as every
+// other request this client makes, so bulk image downloads don't bypass
+// the policy the API calls are held to.
+func (c *Client) FetchFile(ctx context.Context, lang, fileTitle string) ([]byte, error) {
+	name := strings.TrimPrefix(fileTitle, "File:")
+	name = strings.TrimPrefix(name, "Image:")
+	fileURL := fmt.Sprintf("https://%s.wikipedia.org/wiki/Special:FilePath/%s", lang, strings.ReplaceAll(name, " ", "_"))
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fileURL, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	return Article{}, fmt.Errorf("article not found: %s", title)
+	resp, err := c.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("image fetch returned status %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
 }
 
 // GetRandomTitles returns up to limit unique random Wikipedia article titles.
-func GetRandomTitles(limit int) ([]string, error) {
-	apiURL := fmt.Sprintf("https://%s.wikipedia.org/w/api.php", langPrefix)
+func (c *Client) GetRandomTitles(ctx context.Context, limit int) ([]string, error) {
+	apiURL := c.apiURL()
 	uniqueTitles := make(map[string]struct{})
 	var result []string
 
@@ -116,16 +260,15 @@ func GetRandomTitles(limit int) ([]string, error) {
 		params.Set("list", "random")
 		params.Set("rnnamespace", "0")
 		params.Set("rnlimit", fmt.Sprintf("%d", batchSize))
+		params.Set("maxlag", "5")
 		params.Set("format", "json")
 
-		wait()
-		req, err := http.NewRequest("GET", apiURL+"?"+params.Encode(), nil)
+		req, err := http.NewRequestWithContext(ctx, "GET", apiURL+"?"+params.Encode(), nil)
 		if err != nil {
 			return result, err
 		}
-		req.Header.Set("User-Agent", "wiki2docx/1.0 (github.com/w0ikid/wiki2docx)")
 
-		resp, err := wikiClient.Do(req)
+		resp, err := c.do(ctx, req)
 		if err != nil {
 			return result, err
 		}
@@ -0,0 +1,254 @@
+// Package wikitext parses a (small, pragmatic) subset of MediaWiki markup
+// into a flat slice of block-level nodes that output renderers can walk:
+// headings, lists, tables, images and paragraphs of formatted inline runs.
+package wikitext
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Kind identifies the kind of block-level element produced by ParseBlocks.
+type Kind int
+
+const (
+	Paragraph Kind = iota
+	Heading
+	ListItem
+	Table
+	Image
+)
+
+// Run is a span of inline text sharing the same formatting.
+type Run struct {
+	Text   string
+	Bold   bool
+	Italic bool
+	Link   string // hyperlink target; empty for plain text
+}
+
+// Node is a single block-level element parsed out of wikitext.
+type Node struct {
+	Kind    Kind
+	Level   int        // heading level (1-6), or list nesting depth for ListItem
+	Ordered bool       // ListItem: numbered (#) vs bulleted (*)
+	Runs    []Run      // Paragraph, Heading, ListItem
+	Rows    [][]string // Table: plain-text cells, one slice per row
+	Image   string     // Image: the "File:" target, without namespace prefix
+}
+
+var (
+	headingRe  = regexp.MustCompile(`^(={1,6})\s*(.+?)\s*=+$`)
+	fileLineRe = regexp.MustCompile(`^\[\[(?:File|Image):([^\]|]+)`)
+	wikiLinkRe = regexp.MustCompile(`\[\[([^\]|]+)(?:\|([^\]]+))?\]\]`)
+	extLinkRe  = regexp.MustCompile(`\[(https?://\S+?)(?:\s+([^\]]+))?\]`)
+)
+
+// DefaultLangPrefix is the Wikipedia language edition used to resolve
+// [[wikilinks]] into absolute URLs when ParseBlocks is called without a
+// more specific one (see ParseBlocksForLang).
+const DefaultLangPrefix = "en"
+
+// ParseBlocks turns raw MediaWiki wikitext into a flat slice of block
+// nodes, resolving [[wikilinks]] against DefaultLangPrefix. Use
+// ParseBlocksForLang for articles from a different language edition.
+func ParseBlocks(src string) []Node {
+	return ParseBlocksForLang(src, DefaultLangPrefix)
+}
+
+// ParseBlocksForLang is ParseBlocks, resolving [[wikilinks]] against the
+// given language edition instead of DefaultLangPrefix. langPrefix is
+// passed explicitly (rather than through package state) so concurrent
+// callers parsing articles in different languages don't race.
+func ParseBlocksForLang(src, langPrefix string) []Node {
+	lines := strings.Split(src, "\n")
+	var nodes []Node
+	var paraBuf []string
+
+	flushPara := func() {
+		if len(paraBuf) == 0 {
+			return
+		}
+		text := strings.TrimSpace(strings.Join(paraBuf, " "))
+		paraBuf = paraBuf[:0]
+		if text == "" {
+			return
+		}
+		nodes = append(nodes, Node{Kind: Paragraph, Runs: parseInline(text, langPrefix)})
+	}
+
+	i := 0
+	for i < len(lines) {
+		trimmed := strings.TrimSpace(lines[i])
+
+		switch {
+		case trimmed == "":
+			flushPara()
+			i++
+
+		case headingRe.MatchString(trimmed):
+			flushPara()
+			m := headingRe.FindStringSubmatch(trimmed)
+			nodes = append(nodes, Node{
+				Kind:  Heading,
+				Level: len(m[1]),
+				Runs:  parseInline(m[2], langPrefix),
+			})
+			i++
+
+		case fileLineRe.MatchString(trimmed):
+			flushPara()
+			m := fileLineRe.FindStringSubmatch(trimmed)
+			nodes = append(nodes, Node{Kind: Image, Image: strings.TrimSpace(m[1])})
+			i++
+
+		case strings.HasPrefix(trimmed, "*") || strings.HasPrefix(trimmed, "#"):
+			flushPara()
+			level := 0
+			for level < len(trimmed) && (trimmed[level] == '*' || trimmed[level] == '#') {
+				level++
+			}
+			nodes = append(nodes, Node{
+				Kind:    ListItem,
+				Level:   level,
+				Ordered: trimmed[level-1] == '#',
+				Runs:    parseInline(strings.TrimSpace(trimmed[level:]), langPrefix),
+			})
+			i++
+
+		case strings.HasPrefix(trimmed, "{|"):
+			flushPara()
+			var tbl Node
+			tbl, i = parseTable(lines, i)
+			nodes = append(nodes, tbl)
+
+		default:
+			paraBuf = append(paraBuf, trimmed)
+			i++
+		}
+	}
+	flushPara()
+	return nodes
+}
+
+// parseTable consumes a "{| ... |}" block starting at lines[start] and
+// returns the resulting table node plus the index of the line after "|}".
+func parseTable(lines []string, start int) (Node, int) {
+	tbl := Node{Kind: Table}
+	var row []string
+
+	flushRow := func() {
+		if len(row) > 0 {
+			tbl.Rows = append(tbl.Rows, row)
+			row = nil
+		}
+	}
+
+	i := start + 1
+	for i < len(lines) {
+		line := strings.TrimSpace(lines[i])
+		switch {
+		case strings.HasPrefix(line, "|}"):
+			flushRow()
+			return tbl, i + 1
+		case strings.HasPrefix(line, "|-"):
+			flushRow()
+			i++
+		case strings.HasPrefix(line, "!"):
+			for _, cell := range strings.Split(strings.TrimPrefix(line, "!"), "!!") {
+				row = append(row, strings.TrimSpace(cell))
+			}
+			i++
+		case strings.HasPrefix(line, "|"):
+			for _, cell := range strings.Split(strings.TrimPrefix(line, "|"), "||") {
+				row = append(row, strings.TrimSpace(cell))
+			}
+			i++
+		default:
+			i++
+		}
+	}
+	flushRow()
+	return tbl, i
+}
+
+// parseInline splits a line of wikitext into runs, resolving [[links]],
+// external [url text] links and ”'bold”'/”italic” emphasis.
+func parseInline(s, langPrefix string) []Run {
+	var runs []Run
+	for len(s) > 0 {
+		wLoc := wikiLinkRe.FindStringSubmatchIndex(s)
+		eLoc := extLinkRe.FindStringSubmatchIndex(s)
+
+		useWiki := wLoc != nil && (eLoc == nil || wLoc[0] <= eLoc[0])
+		useExt := !useWiki && eLoc != nil
+
+		switch {
+		case useWiki:
+			runs = append(runs, parseEmphasis(s[:wLoc[0]])...)
+			target := s[wLoc[2]:wLoc[3]]
+			text := target
+			if wLoc[4] != -1 {
+				text = s[wLoc[4]:wLoc[5]]
+			}
+			runs = append(runs, Run{Text: text, Link: articleURL(target, langPrefix)})
+			s = s[wLoc[1]:]
+
+		case useExt:
+			runs = append(runs, parseEmphasis(s[:eLoc[0]])...)
+			target := s[eLoc[2]:eLoc[3]]
+			text := target
+			if eLoc[4] != -1 {
+				text = s[eLoc[4]:eLoc[5]]
+			}
+			runs = append(runs, Run{Text: text, Link: target})
+			s = s[eLoc[1]:]
+
+		default:
+			runs = append(runs, parseEmphasis(s)...)
+			s = ""
+		}
+	}
+	return runs
+}
+
+// parseEmphasis splits plain wikitext (no links) into runs, toggling
+// bold/italic state on ”' and ” markers.
+func parseEmphasis(s string) []Run {
+	var runs []Run
+	bold, italic := false, false
+	var buf strings.Builder
+
+	flush := func() {
+		if buf.Len() == 0 {
+			return
+		}
+		runs = append(runs, Run{Text: buf.String(), Bold: bold, Italic: italic})
+		buf.Reset()
+	}
+
+	for i := 0; i < len(s); {
+		switch {
+		case strings.HasPrefix(s[i:], "'''"):
+			flush()
+			bold = !bold
+			i += 3
+		case strings.HasPrefix(s[i:], "''"):
+			flush()
+			italic = !italic
+			i += 2
+		default:
+			buf.WriteByte(s[i])
+			i++
+		}
+	}
+	flush()
+	return runs
+}
+
+// articleURL builds a canonical wikipedia.org URL for a [[wikilink]] target.
+func articleURL(target, langPrefix string) string {
+	target = strings.SplitN(target, "#", 2)[0]
+	target = strings.TrimSpace(target)
+	return "https://" + langPrefix + ".wikipedia.org/wiki/" + strings.ReplaceAll(target, " ", "_")
+}
@@ -0,0 +1,153 @@
+package wikitext
+
+import "testing"
+
+func TestParseBlocksForLangHeading(t *testing.T) {
+	nodes := ParseBlocksForLang("== History ==", "en")
+	if len(nodes) != 1 {
+		t.Fatalf("got %d nodes, want 1", len(nodes))
+	}
+	n := nodes[0]
+	if n.Kind != Heading || n.Level != 2 {
+		t.Fatalf("got Kind=%v Level=%d, want Heading level 2", n.Kind, n.Level)
+	}
+	if got := runsText(n.Runs); got != "History" {
+		t.Fatalf("got runs %q, want %q", got, "History")
+	}
+}
+
+func TestParseBlocksForLangParagraph(t *testing.T) {
+	nodes := ParseBlocksForLang("Hello\nworld\n\nSecond paragraph.", "en")
+	if len(nodes) != 2 {
+		t.Fatalf("got %d nodes, want 2", len(nodes))
+	}
+	if got := runsText(nodes[0].Runs); got != "Hello world" {
+		t.Fatalf("got %q, want %q", got, "Hello world")
+	}
+	if got := runsText(nodes[1].Runs); got != "Second paragraph." {
+		t.Fatalf("got %q, want %q", got, "Second paragraph.")
+	}
+}
+
+func TestParseBlocksForLangList(t *testing.T) {
+	nodes := ParseBlocksForLang("* one\n# two", "en")
+	if len(nodes) != 2 {
+		t.Fatalf("got %d nodes, want 2", len(nodes))
+	}
+	if nodes[0].Kind != ListItem || nodes[0].Ordered {
+		t.Fatalf("first item: got Kind=%v Ordered=%v, want unordered ListItem", nodes[0].Kind, nodes[0].Ordered)
+	}
+	if nodes[1].Kind != ListItem || !nodes[1].Ordered {
+		t.Fatalf("second item: got Kind=%v Ordered=%v, want ordered ListItem", nodes[1].Kind, nodes[1].Ordered)
+	}
+}
+
+func TestParseBlocksForLangImage(t *testing.T) {
+	nodes := ParseBlocksForLang("[[File:Example.png|thumb]]", "en")
+	if len(nodes) != 1 || nodes[0].Kind != Image {
+		t.Fatalf("got %+v, want single Image node", nodes)
+	}
+	if nodes[0].Image != "Example.png" {
+		t.Fatalf("got Image %q, want %q", nodes[0].Image, "Example.png")
+	}
+}
+
+func TestParseBlocksForLangTable(t *testing.T) {
+	src := "{|\n! A !! B\n|-\n| 1 || 2\n|}"
+	nodes := ParseBlocksForLang(src, "en")
+	if len(nodes) != 1 || nodes[0].Kind != Table {
+		t.Fatalf("got %+v, want single Table node", nodes)
+	}
+	want := [][]string{{"A", "B"}, {"1", "2"}}
+	rows := nodes[0].Rows
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(rows), len(want))
+	}
+	for i := range want {
+		if len(rows[i]) != len(want[i]) {
+			t.Fatalf("row %d: got %v, want %v", i, rows[i], want[i])
+		}
+		for j := range want[i] {
+			if rows[i][j] != want[i][j] {
+				t.Fatalf("row %d cell %d: got %q, want %q", i, j, rows[i][j], want[i][j])
+			}
+		}
+	}
+}
+
+func TestParseInlineWikiLink(t *testing.T) {
+	runs := parseInline("See [[Go (programming language)|Go]] for details.", "en")
+	var found bool
+	for _, r := range runs {
+		if r.Link == "" {
+			continue
+		}
+		found = true
+		if r.Text != "Go" {
+			t.Fatalf("got link text %q, want %q", r.Text, "Go")
+		}
+		if r.Link != "https://en.wikipedia.org/wiki/Go_(programming_language)" {
+			t.Fatalf("got link %q, want the en.wikipedia.org URL", r.Link)
+		}
+	}
+	if !found {
+		t.Fatal("no link run found")
+	}
+}
+
+func TestParseInlineExternalLink(t *testing.T) {
+	runs := parseInline("[https://example.com Example]", "en")
+	var found bool
+	for _, r := range runs {
+		if r.Link == "https://example.com" {
+			found = true
+			if r.Text != "Example" {
+				t.Fatalf("got link text %q, want %q", r.Text, "Example")
+			}
+		}
+	}
+	if !found {
+		t.Fatal("no external link run found")
+	}
+}
+
+func TestParseInlineDifferentLangPrefixes(t *testing.T) {
+	enRuns := parseInline("[[Berlin]]", "en")
+	deRuns := parseInline("[[Berlin]]", "de")
+	if enRuns[0].Link == deRuns[0].Link {
+		t.Fatalf("expected different URLs for different lang prefixes, both got %q", enRuns[0].Link)
+	}
+	if enRuns[0].Link != "https://en.wikipedia.org/wiki/Berlin" {
+		t.Fatalf("got %q", enRuns[0].Link)
+	}
+	if deRuns[0].Link != "https://de.wikipedia.org/wiki/Berlin" {
+		t.Fatalf("got %q", deRuns[0].Link)
+	}
+}
+
+func TestParseEmphasis(t *testing.T) {
+	runs := parseEmphasis("plain '''bold''' ''italic'' end")
+	var sawBold, sawItalic bool
+	for _, r := range runs {
+		if r.Bold && r.Text == "bold" {
+			sawBold = true
+		}
+		if r.Italic && r.Text == "italic" {
+			sawItalic = true
+		}
+	}
+	if !sawBold {
+		t.Fatal("expected a bold run containing \"bold\"")
+	}
+	if !sawItalic {
+		t.Fatal("expected an italic run containing \"italic\"")
+	}
+}
+
+func runsText(runs []Run) string {
+	var s string
+	for _, r := range runs {
+		s += r.Text
+	}
+	return s
+}
@@ -2,26 +2,52 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/w0ikid/wiki2docx/internal/docx"
+	"github.com/w0ikid/wiki2docx/internal/jobs"
+	"github.com/w0ikid/wiki2docx/internal/render"
+	_ "github.com/w0ikid/wiki2docx/internal/render/docx"
+	_ "github.com/w0ikid/wiki2docx/internal/render/epub"
+	_ "github.com/w0ikid/wiki2docx/internal/render/markdown"
+	_ "github.com/w0ikid/wiki2docx/internal/render/pdf"
 	"github.com/w0ikid/wiki2docx/internal/wiki"
+	"github.com/w0ikid/wiki2docx/internal/wiki/dump"
+)
+
+// Retry backoff bounds for titles that previously failed; see jobs.Backoff.
+const (
+	retryBaseDelay = 2 * time.Second
+	retryMaxDelay  = 5 * time.Minute
 )
 
 func main() {
 	var (
-		inputFile = flag.String("input", "", "Path to a .txt file with article titles (one per line)")
-		randomN   = flag.Int("random", 1, "Number of random articles to fetch (used when -input is not set)")
-		lang      = flag.String("lang", "en", "Wikipedia language prefix (e.g. en, ru, de)")
-		nWorkers  = flag.Int("workers", 5, "Number of concurrent workers")
-		outDir    = flag.String("out", "./output", "Directory to save DOCX files")
+		inputFile   = flag.String("input", "", "Path to a .txt file with article titles (one per line)")
+		randomN     = flag.Int("random", 1, "Number of articles to fetch (random, unless -category or -search is set)")
+		category    = flag.String("category", "", `Fetch articles from a category, e.g. "Category:Physics" (takes priority over -random)`)
+		search      = flag.String("search", "", "Fetch articles matching a full-text search query (takes priority over -random)")
+		depth       = flag.Int("depth", 1, "How many levels of subcategories to recurse into for -category (0 = that category only)")
+		lang        = flag.String("lang", "en", "Wikipedia language prefix (e.g. en, ru, de)")
+		nWorkers    = flag.Int("workers", 5, "Number of concurrent workers")
+		outDir      = flag.String("out", "./output", "Directory to save output files")
+		dumpFile    = flag.String("dump", "", "Path to a pages-articles-multistream.xml.bz2 dump (offline mode, takes priority over -input/-random)")
+		dumpIndex   = flag.String("dump-index", "", "Path to the dump's multistream index .txt.bz2 (defaults to the file next to -dump)")
+		formats     = flag.String("format", "docx", fmt.Sprintf("Comma-separated output formats to render (available: %s)", strings.Join(render.Names(), ", ")))
+		bundle      = flag.Bool("bundle", false, "Pack all articles into a single multi-chapter output instead of one file per article (docx, epub)")
+		bundleName  = flag.String("bundle-name", "bundle", "Base filename (without extension) for -bundle output")
+		statePath   = flag.String("state", "./wiki2docx-state.db", "Path to the BoltDB job-state file used to resume interrupted runs")
+		maxAttempts = flag.Int("max-attempts", 5, "Give up on a title after this many failed attempts")
+		summaryPath = flag.String("summary", "", "Path to write a JSON run summary (defaults to <out>/summary.json)")
 
 		// Aliases
 		workerAlias = flag.Int("worker", 0, "Alias for -workers")
@@ -58,9 +84,24 @@ func main() {
 	// Set a reasonable timeout for HTTP requests to prevent hangs.
 	http.DefaultClient.Timeout = 30 * time.Second
 
+	renderers, err := resolveRenderers(*formats)
+	if err != nil {
+		log.Fatalf("Invalid -format: %v", err)
+	}
+	if *bundle && !anyBundleCapable(renderers) {
+		log.Fatalf("-bundle requires at least one bundle-capable format in -format (docx, epub)")
+	}
+
+	if *dumpFile != "" {
+		if err := runDumpMode(*dumpFile, *dumpIndex, *outDir, *lang, *nWorkers, renderers, *bundle, *bundleName); err != nil {
+			log.Fatalf("Dump ingestion failed: %v", err)
+		}
+		return
+	}
+
 	// --- Collect titles ---
 	fmt.Printf("Collecting article titles (random: %d, lang: %s)...\n", *randomN, *lang)
-	titles, err := collectTitles(*inputFile, *randomN)
+	titles, err := collectTitles(*inputFile, *category, *search, *randomN, *depth)
 	if err != nil {
 		log.Fatalf("Failed to collect titles: %v", err)
 	}
@@ -70,28 +111,94 @@ func main() {
 
 	fmt.Printf("Processing %d article(s) with %d worker(s)...\n", len(titles), *nWorkers)
 
+	store, err := jobs.Open(*statePath)
+	if err != nil {
+		log.Fatalf("Failed to open job state (%s): %v", *statePath, err)
+	}
+
+	started := time.Now()
+	target := jobTarget(renderers, *bundle)
+
 	// --- Worker pool ---
-	titlesCh := make(chan string, len(titles))
+	var jobsToRun []titleJob
 	for _, t := range titles {
-		titlesCh <- t
+		rec, ok, err := store.Get(*lang, t, target)
+		if err != nil {
+			store.Close()
+			log.Fatalf("Failed to read job state for %q: %v", t, err)
+		}
+		if ok && rec.Status == jobs.StatusRendered {
+			fmt.Printf("  [SKIP] %s (already rendered)\n", t)
+			continue
+		}
+		if ok && rec.Status == jobs.StatusFailed && rec.Attempts >= *maxAttempts {
+			fmt.Printf("  [SKIP] %s (failed %d times, giving up)\n", t, rec.Attempts)
+			continue
+		}
+		attempts := 0
+		if ok {
+			attempts = rec.Attempts
+		}
+		jobsToRun = append(jobsToRun, titleJob{Title: t, Attempts: attempts})
+	}
+
+	titlesCh := make(chan titleJob, len(jobsToRun))
+	for _, j := range jobsToRun {
+		titlesCh <- j
 	}
 	close(titlesCh)
 
+	ctx := context.Background()
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 	var failed []string
+	var bundled []render.Article
+	bundledAttempts := map[string]int{}
 
 	for i := 0; i < *nWorkers; i++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			for title := range titlesCh {
-				if err := processArticle(title, *outDir); err != nil {
+			for job := range titlesCh {
+				title := job.Title
+				if job.Attempts > 0 {
+					time.Sleep(jobs.Backoff(job.Attempts, retryBaseDelay, retryMaxDelay))
+				}
+
+				article, err := wiki.FetchArticle(title)
+				if err != nil {
+					store.Save(jobs.Record{Lang: *lang, Title: title, Target: target, Status: jobs.StatusFailed, Attempts: job.Attempts + 1, LastError: err.Error()})
+					mu.Lock()
+					failed = append(failed, fmt.Sprintf("%s: %v", title, err))
+					mu.Unlock()
+					fmt.Printf("  [FAIL] %s: %v\n", title, err)
+					continue
+				}
+				store.Save(jobs.Record{Lang: *lang, Title: title, Target: target, Status: jobs.StatusFetched, Attempts: job.Attempts + 1})
+
+				a := render.Article{Title: article.Title, Wikitext: article.Content, Lang: *lang}
+				if *bundle {
+					// Not yet StatusRendered: the bundle file itself is only
+					// written once, after every article has been fetched, by
+					// renderBundle below. Marking this "rendered" now would
+					// let a resumed run skip titles whose bundle was never
+					// actually produced.
+					mu.Lock()
+					bundled = append(bundled, a)
+					bundledAttempts[title] = job.Attempts + 1
+					mu.Unlock()
+					fmt.Printf("  [OK]   %s\n", title)
+					continue
+				}
+
+				if err := renderArticle(ctx, renderers, a, *outDir); err != nil {
+					store.Save(jobs.Record{Lang: *lang, Title: title, Target: target, Status: jobs.StatusFailed, Attempts: job.Attempts + 1, LastError: err.Error()})
 					mu.Lock()
 					failed = append(failed, fmt.Sprintf("%s: %v", title, err))
 					mu.Unlock()
 					fmt.Printf("  [FAIL] %s: %v\n", title, err)
 				} else {
+					store.Save(jobs.Record{Lang: *lang, Title: title, Target: target, Status: jobs.StatusRendered, Attempts: job.Attempts + 1})
 					fmt.Printf("  [OK]   %s\n", title)
 				}
 			}
@@ -100,17 +207,141 @@ func main() {
 
 	wg.Wait()
 
+	if *bundle {
+		if err := renderBundle(ctx, renderers, bundled, *outDir, *bundleName); err != nil {
+			fmt.Printf("Bundle render failed: %v\n", err)
+			store.Close()
+			os.Exit(1)
+		}
+		for _, a := range bundled {
+			store.Save(jobs.Record{Lang: *lang, Title: a.Title, Target: target, Status: jobs.StatusRendered, Attempts: bundledAttempts[a.Title]})
+		}
+	}
+
 	fmt.Printf("\nDone. %d succeeded, %d failed.\n", len(titles)-len(failed), len(failed))
 	for _, f := range failed {
 		fmt.Println("  ERROR:", f)
 	}
+
+	if err := writeRunSummary(store, started, *summaryPath, *outDir); err != nil {
+		fmt.Printf("  [WARN] failed to write summary: %v\n", err)
+	}
+	store.Close()
+}
+
+// jobTarget fingerprints what a run is producing (the sorted renderer
+// set, plus whether it's a -bundle run) so the job store can tell apart
+// "rendered as docx" from "rendered as epub" for the same title.
+func jobTarget(renderers []render.Renderer, bundle bool) string {
+	names := make([]string, len(renderers))
+	for i, r := range renderers {
+		names[i] = r.Name()
+	}
+	sort.Strings(names)
+	target := strings.Join(names, "+")
+	if bundle {
+		target += "+bundle"
+	}
+	return target
+}
+
+// titleJob is a title queued for fetch+render, carrying the number of
+// prior failed attempts so the worker can apply jobs.Backoff before
+// retrying it.
+type titleJob struct {
+	Title    string
+	Attempts int
+}
+
+// writeRunSummary gathers every job record and writes an end-of-run
+// summary to path, defaulting to "<outDir>/summary.json" if path is empty.
+func writeRunSummary(store *jobs.Store, started time.Time, path, outDir string) error {
+	records, err := store.All()
+	if err != nil {
+		return fmt.Errorf("read job state: %w", err)
+	}
+	if path == "" {
+		path = filepath.Join(outDir, "summary.json")
+	}
+	summary := jobs.BuildSummary(records, started, time.Now())
+	return jobs.WriteSummary(summary, path)
+}
+
+// resolveRenderers parses a comma-separated -format value into the
+// matching registered render.Renderer list.
+func resolveRenderers(formats string) ([]render.Renderer, error) {
+	var out []render.Renderer
+	for _, name := range strings.Split(formats, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		r, err := render.Get(name)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no output formats given")
+	}
+	return out, nil
+}
+
+func anyBundleCapable(renderers []render.Renderer) bool {
+	for _, r := range renderers {
+		if _, ok := r.(render.BundleRenderer); ok {
+			return true
+		}
+	}
+	return false
 }
 
-// collectTitles returns a list of article titles either from a file or from Wikipedia's random endpoint.
-func collectTitles(inputFile string, randomN int) ([]string, error) {
+// renderArticle runs a into every selected renderer, stopping at the
+// first error.
+func renderArticle(ctx context.Context, renderers []render.Renderer, a render.Article, outDir string) error {
+	for _, r := range renderers {
+		if err := r.Render(ctx, a, outDir); err != nil {
+			return fmt.Errorf("%s: %w", r.Name(), err)
+		}
+	}
+	return nil
+}
+
+// renderBundle packs articles into one multi-chapter file per
+// bundle-capable renderer in renderers; non-bundle-capable formats are
+// skipped since there's no single-file equivalent to fall back to.
+func renderBundle(ctx context.Context, renderers []render.Renderer, articles []render.Article, outDir, bundleName string) error {
+	if len(articles) == 0 {
+		return nil
+	}
+	for _, r := range renderers {
+		br, ok := r.(render.BundleRenderer)
+		if !ok {
+			fmt.Printf("  [SKIP] %s does not support -bundle\n", r.Name())
+			continue
+		}
+		fmt.Printf("Bundling %d article(s) into %s.%s...\n", len(articles), bundleName, br.Name())
+		if err := br.RenderBundle(ctx, articles, outDir, bundleName); err != nil {
+			return fmt.Errorf("%s: %w", br.Name(), err)
+		}
+	}
+	return nil
+}
+
+// collectTitles returns a list of article titles from, in priority
+// order, a title file, a category, a search query, or (failing all of
+// those) Wikipedia's random endpoint.
+func collectTitles(inputFile, category, search string, randomN, depth int) ([]string, error) {
 	if inputFile != "" {
 		return readTitlesFromFile(inputFile)
 	}
+	if category != "" {
+		return wiki.GetCategoryMembers(category, depth > 0, depth, randomN)
+	}
+	if search != "" {
+		return wiki.SearchTitles(search, randomN)
+	}
 	return wiki.GetRandomTitles(randomN)
 }
 
@@ -134,14 +365,83 @@ func readTitlesFromFile(path string) ([]string, error) {
 	return titles, scanner.Err()
 }
 
-// processArticle fetches a Wikipedia article and writes it to a DOCX file.
-func processArticle(title, outDir string) error {
-	article, err := wiki.FetchArticle(title)
+// runDumpMode streams articles out of an offline XML dump into the same
+// kind of worker pool used for live fetches, without fetching articles
+// from the API since the dump already carries each article's wikitext.
+func runDumpMode(dumpPath, indexPath, outDir, lang string, nWorkers int, renderers []render.Renderer, bundle bool, bundleName string) error {
+	if indexPath == "" {
+		indexPath = deriveDumpIndexPath(dumpPath)
+	}
+
+	fmt.Printf("Streaming articles from dump %s (index: %s)...\n", dumpPath, indexPath)
+	ds, err := dump.Open(dumpPath, indexPath)
 	if err != nil {
-		return fmt.Errorf("fetch: %w", err)
+		return err
+	}
+	ds.Concurrency = nWorkers
+
+	articlesCh := make(chan *dump.Article, nWorkers*2)
+	go func() {
+		defer close(articlesCh)
+		if err := ds.PushTo(articlesCh); err != nil {
+			fmt.Printf("  [ERROR] dump decode: %v\n", err)
+		}
+	}()
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var ok, failed int
+	var bundled []render.Article
+
+	for i := 0; i < nWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for a := range articlesCh {
+				article := render.Article{Title: a.Title, Wikitext: a.Text, Lang: lang}
+				if bundle {
+					mu.Lock()
+					bundled = append(bundled, article)
+					ok++
+					mu.Unlock()
+					fmt.Printf("  [OK]   %s\n", a.Title)
+					continue
+				}
+
+				if err := renderArticle(ctx, renderers, article, outDir); err != nil {
+					mu.Lock()
+					failed++
+					mu.Unlock()
+					fmt.Printf("  [FAIL] %s: %v\n", a.Title, err)
+				} else {
+					mu.Lock()
+					ok++
+					mu.Unlock()
+					fmt.Printf("  [OK]   %s\n", a.Title)
+				}
+			}
+		}()
 	}
-	if err := docx.Build(article.Title, article.Content, outDir); err != nil {
-		return fmt.Errorf("build docx: %w", err)
+	wg.Wait()
+
+	if bundle {
+		if err := renderBundle(ctx, renderers, bundled, outDir, bundleName); err != nil {
+			return err
+		}
 	}
+
+	fmt.Printf("\nDone. %d succeeded, %d failed.\n", ok, failed)
 	return nil
 }
+
+// deriveDumpIndexPath guesses the multistream index path from the dump's
+// conventional filename, e.g. "enwiki-...-pages-articles-multistream.xml.bz2"
+// -> "enwiki-...-pages-articles-multistream-index.txt.bz2".
+func deriveDumpIndexPath(dumpPath string) string {
+	const suffix = "multistream.xml.bz2"
+	if strings.HasSuffix(dumpPath, suffix) {
+		return strings.TrimSuffix(dumpPath, suffix) + "multistream-index.txt.bz2"
+	}
+	return strings.TrimSuffix(dumpPath, ".xml.bz2") + "-index.txt.bz2"
+}